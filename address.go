@@ -0,0 +1,199 @@
+package ipldeml
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// contains helpers for parsing and normalizing addresses
+
+// parseAddresses parses every address field, reading header values with get.
+// prefix is prepended to the field names, and is used for resent fields
+func parseAddresses(get func(key string) string, prefix string) pb.Addresses {
+	var addrs pb.Addresses
+	addrs.Sender = parseAddress(get(prefix + "Sender"))
+	addrs.From = parseAddressList(get(prefix + "From"))
+	addrs.ReplyTo, addrs.ReplyToGroups = parseAddressField(get(prefix + "Reply-To"))
+	addrs.To, addrs.ToGroups = parseAddressField(get(prefix + "To"))
+	addrs.Cc, addrs.CcGroups = parseAddressField(get(prefix + "Cc"))
+	addrs.Bcc, addrs.BccGroups = parseAddressField(get(prefix + "Bcc"))
+	return addrs
+}
+
+// parseAddress parses a single address, returning nil if it is missing or invalid
+func parseAddress(value string) *pb.Address {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	addr, err := addressParser.Parse(value)
+	if err != nil {
+		return nil
+	}
+	return &pb.Address{
+		Name:    validUTF8(addr.Name),
+		Address: validUTF8(addr.Address),
+	}
+}
+
+// parseAddressList parses a list of addresses, returning nil if it is missing or invalid
+func parseAddressList(value string) []pb.Address {
+	addrs, _ := parseAddressField(value)
+	return addrs
+}
+
+// parseAddressField parses an address list which may contain groups. group
+// members are included in the returned addresses, and referenced by the groups
+func parseAddressField(value string) ([]pb.Address, []pb.Group) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var (
+		addrs  []pb.Address
+		groups []pb.Group
+	)
+	for _, item := range splitAddressList(value) {
+		var list []pb.Address
+		if strings.TrimSpace(item.list) != "" {
+			parsed, err := addressParser.ParseList(item.list)
+			if err != nil {
+				return nil, nil
+			}
+			for _, v := range parsed {
+				list = append(list, pb.Address{
+					Name:    validUTF8(v.Name),
+					Address: validUTF8(v.Address),
+				})
+			}
+		}
+		if item.group {
+			group := pb.Group{Name: decodeHeader(item.name)}
+			for i := range list {
+				group.Members = append(group.Members, int32(len(addrs)+i))
+			}
+			groups = append(groups, group)
+		}
+		addrs = append(addrs, list...)
+	}
+	return addrs, groups
+}
+
+// addressItem is either a single address or a group from an address list
+type addressItem struct {
+	group bool
+	name  string
+	list  string
+}
+
+// splitAddressList splits an address list at the top level commas and groups,
+// ignoring anything inside quotes, comments and angle brackets
+func splitAddressList(value string) []addressItem {
+	var (
+		items           []addressItem
+		start, comments int
+		angles          int
+		quoted, escaped bool
+		inGroup         bool
+		groupName       string
+	)
+	addPlain := func(text string) {
+		if strings.TrimSpace(text) != "" {
+			items = append(items, addressItem{list: text})
+		}
+	}
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		switch {
+		case escaped:
+			escaped = false
+		case ch == '\\' && (quoted || comments > 0):
+			escaped = true
+		case quoted:
+			quoted = ch != '"'
+		case ch == '(':
+			comments++
+		case comments > 0:
+			if ch == ')' {
+				comments--
+			}
+		case ch == '"':
+			quoted = true
+		case ch == '<':
+			angles++
+		case ch == '>' && angles > 0:
+			angles--
+		case angles > 0:
+		case ch == ':' && !inGroup:
+			inGroup, groupName, start = true, strings.TrimSpace(value[start:i]), i+1
+		case ch == ';' && inGroup:
+			items = append(items, addressItem{group: true, name: groupName, list: value[start:i]})
+			inGroup, start = false, i+1
+		case ch == ',' && !inGroup:
+			addPlain(value[start:i])
+			start = i + 1
+		}
+	}
+	if inGroup {
+		// tolerate a missing group terminator
+		items = append(items, addressItem{group: true, name: groupName, list: value[start:]})
+	} else {
+		addPlain(value[start:])
+	}
+	return items
+}
+
+// normalizeAddress sets the normalized form of an address, lower casing the
+// domain and converting it to punycode, and putting the local part in NFC
+func normalizeAddress(addr *pb.Address) {
+	i := strings.LastIndex(addr.Address, "@")
+	if i < 0 {
+		return
+	}
+	local, domain := norm.NFC.String(addr.Address[:i]), strings.ToLower(addr.Address[i+1:])
+	if ascii, err := idna.Lookup.ToASCII(domain); err == nil {
+		domain = ascii
+	}
+	addr.Smtputf8 = !isASCII(local)
+	if normalized := local + "@" + domain; normalized != addr.Address {
+		addr.Normalized = normalized
+	}
+}
+
+// NormalizedAddress returns the normalized form of an address if one was
+// stored, and the address as written otherwise
+func NormalizedAddress(addr pb.Address) string {
+	if addr.Normalized != "" {
+		return addr.Normalized
+	}
+	return addr.Address
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// forEachAddress calls fn with every address in the email
+func forEachAddress(email *pb.Email, fn func(*pb.Address)) {
+	each := func(addrs *pb.Addresses) {
+		if addrs.Sender != nil {
+			fn(addrs.Sender)
+		}
+		for _, list := range [][]pb.Address{addrs.From, addrs.ReplyTo, addrs.To, addrs.Cc, addrs.Bcc} {
+			for i := range list {
+				fn(&list[i])
+			}
+		}
+	}
+	each(&email.Addresses)
+	for i := range email.ResentBlocks {
+		each(&email.ResentBlocks[i].Addresses)
+	}
+}
@@ -0,0 +1,69 @@
+package ipldeml
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestParseAddressField(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		wantAddrs  []string
+		wantGroups []pb.Group
+	}{
+		{"plain", "a@x.com, \"Doe, Jane\" <b@y.com>", []string{"a@x.com", "b@y.com"}, nil},
+		{"empty group", "undisclosed-recipients:;", nil, []pb.Group{{Name: "undisclosed-recipients"}}},
+		{"group", "Team: a@x.com, B <b@y.com>;, c@z.com", []string{"a@x.com", "b@y.com", "c@z.com"}, []pb.Group{{Name: "Team", Members: []int32{0, 1}}}},
+		{"quoted colon", "\"Re: Team\" <a@x.com>, Ops (on: call): b@y.com;", []string{"a@x.com", "b@y.com"}, []pb.Group{{Name: "Ops (on: call)", Members: []int32{1}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addrs, groups := parseAddressField(tt.value)
+			if len(addrs) != len(tt.wantAddrs) {
+				t.Fatalf("got %v addresses, want %v", len(addrs), len(tt.wantAddrs))
+			}
+			for i, addr := range addrs {
+				if addr.Address != tt.wantAddrs[i] {
+					t.Errorf("address %v = %s, want %s", i, addr.Address, tt.wantAddrs[i])
+				}
+			}
+			if len(groups) != len(tt.wantGroups) {
+				t.Fatalf("got %v groups, want %v", len(groups), len(tt.wantGroups))
+			}
+			for i, group := range groups {
+				if !proto.Equal(&group, &tt.wantGroups[i]) {
+					t.Errorf("group %v = %v, want %v", i, group, tt.wantGroups[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeAddresses(t *testing.T) {
+	eml := "From: Jane <Jane@Example.COM>\r\nTo: user@bücher.example, ñandú@example.com\r\nSubject: hi\r\n\r\nhello\r\n"
+	converter := NewConverterWithOpts(context.Background(), nil, Opts{NormalizeAddresses: true})
+	email, err := converter.Convert(strings.NewReader(eml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := NormalizedAddress(email.Addresses.From[0]); got != "Jane@example.com" {
+		t.Fatal("bad normalized from", got)
+	}
+	if email.Addresses.From[0].Address != "Jane@Example.COM" {
+		t.Fatal("original address not kept")
+	}
+	if got := NormalizedAddress(email.Addresses.To[0]); got != "user@xn--bcher-kva.example" {
+		t.Fatal("bad punycode domain", got)
+	}
+	if email.Addresses.To[0].Smtputf8 || !email.Addresses.To[1].Smtputf8 {
+		t.Fatal("bad smtputf8 flag")
+	}
+	if email.Addresses.To[1].Normalized != "" {
+		t.Fatal("normalized form should be omitted when unchanged")
+	}
+}
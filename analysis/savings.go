@@ -0,0 +1,153 @@
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io/ioutil"
+	"path/filepath"
+
+	ipldeml "github.com/RTradeLtd/ipld-eml"
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// size of the field linking an email to its header template
+const templateLinkSize = 48
+
+// HTMLChunkReport compares storing html bodies whole against chunking them at
+// block element boundaries. sizes ignore the overhead of the objects themselves
+type HTMLChunkReport struct {
+	Emails int
+	// TotalSize is the size of every html body
+	TotalSize int64
+	// WholeSize is the size of the unique html bodies
+	WholeSize int64
+	// ChunkedSize is the size of the unique html chunks stored as objects of
+	// their own, plus the chunks left inline in every email
+	ChunkedSize int64
+	// InlineSize is the part of ChunkedSize left inline
+	InlineSize int64
+}
+
+// HTMLChunkSavings reads the emails in dir, and reports how much space chunking
+// their html bodies saves compared to storing each body whole. the bodies are
+// those of the converted emails, chunked the same way as stored emails
+func HTMLChunkSavings(dir string) (*HTMLChunkReport, error) {
+	var (
+		report    = new(HTMLChunkReport)
+		converter = ipldeml.NewConverter(context.Background(), nil)
+		bodies    = make(map[[32]byte]bool)
+		chunks    = make(map[[32]byte]bool)
+	)
+	err := readEmails(dir, func(data []byte) error {
+		email, err := converter.Convert(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		if email.HtmlBody == "" {
+			return nil
+		}
+		report.Emails++
+		report.TotalSize += int64(len(email.HtmlBody))
+		if sum := sha256.Sum256([]byte(email.HtmlBody)); !bodies[sum] {
+			bodies[sum] = true
+			report.WholeSize += int64(len(email.HtmlBody))
+		}
+		for _, chunk := range ipldeml.SplitHTMLBlocks(email.HtmlBody) {
+			if !ipldeml.IsStoredHTMLBlock(chunk) {
+				report.ChunkedSize += int64(len(chunk))
+				report.InlineSize += int64(len(chunk))
+				continue
+			}
+			if sum := sha256.Sum256([]byte(chunk)); !chunks[sum] {
+				chunks[sum] = true
+				report.ChunkedSize += int64(len(chunk))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// HeaderTemplateReport compares storing the headers of each email in full
+// against sharing header templates between emails
+type HeaderTemplateReport struct {
+	Emails int
+	// Templates is the number of unique header templates
+	Templates int
+	// TotalSize is the size of every headers map
+	TotalSize int64
+	// SharedSize is the size of the headers left on each email, their links
+	// to templates, and the unique templates
+	SharedSize int64
+}
+
+// HeaderTemplateSavings reads the emails in dir, and reports how much space
+// sharing the header values which repeat between them as templates saves
+// compared to storing every header with its email. the headers are those of
+// the converted emails, split the same way as stored emails
+func HeaderTemplateSavings(dir string) (*HeaderTemplateReport, error) {
+	var (
+		report    = new(HeaderTemplateReport)
+		converter = ipldeml.NewConverter(context.Background(), nil)
+		counts    = make(ipldeml.HeaderCounts)
+		headers   []pb.Header
+		templates = make(map[[32]byte]bool)
+	)
+	err := readEmails(dir, func(data []byte) error {
+		email, err := converter.Convert(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		counts.Add(email.Headers)
+		headers = append(headers, email.Headers)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, header := range headers {
+		report.Emails++
+		report.TotalSize += int64(header.Size())
+		template, rest := ipldeml.SplitHeaderTemplate(header, counts)
+		if template == nil {
+			report.SharedSize += int64(header.Size())
+			continue
+		}
+		report.SharedSize += int64(rest.Size()) + templateLinkSize
+		templateData, err := template.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		if sum := sha256.Sum256(templateData); !templates[sum] {
+			templates[sum] = true
+			report.Templates++
+			report.SharedSize += int64(len(templateData))
+		}
+	}
+	return report, nil
+}
+
+// readEmails calls fn with the contents of every email in dir
+func readEmails(dir string, fn func(data []byte) error) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return err
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
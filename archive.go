@@ -0,0 +1,284 @@
+package ipldeml
+
+import (
+	"crypto/sha256"
+	"errors"
+	"path/filepath"
+	"sort"
+
+	xpb "github.com/RTradeLtd/TxPB/v3/go"
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains the archive, a hamt of ArchiveNode objects whose root links every
+// email added to it, so a single hash identifies a whole archive
+
+// slots holding more links than this are split into a child node
+const maxArchiveBucketSize = 16
+
+// ErrNotInArchive is returned when looking up a key which is not in an archive
+var ErrNotInArchive = errors.New("key not in archive")
+
+// archiveNodeStore stores the nodes of an archive
+type archiveNodeStore interface {
+	getArchiveNode(hash string) (*pb.ArchiveNode, error)
+	putArchiveNode(node *pb.ArchiveNode) (string, error)
+}
+
+// archiveIndex returns the slot of key in a node at depth
+func archiveIndex(key string, depth int) uint32 {
+	digest := sha256.Sum256([]byte(key))
+	return uint32(digest[depth])
+}
+
+// AddToArchive adds emails to the archive with the given root, replacing any
+// emails already linked under the same keys, and returns the new root. an
+// empty root starts a new archive
+func (c *Converter) AddToArchive(root string, links ...pb.ArchiveLink) (string, error) {
+	node := new(pb.ArchiveNode)
+	if root != "" {
+		var err error
+		if node, err = c.getArchiveNode(root); err != nil {
+			return "", err
+		}
+	}
+	if _, err := addToArchiveNode(c, node, 0, links); err != nil {
+		return "", err
+	}
+	return c.putArchiveNode(node)
+}
+
+// AddFromDirectoryToArchive is like AddFromDirectory, but also adds the
+// emails to the archive with the given root keyed by their path, the
+// directory as given joined with their file name, so emails with the same
+// file name in different directories are kept apart. returns the new root
+func (c *Converter) AddFromDirectoryToArchive(dir, root string) (map[string]string, string, error) {
+	hashes, err := c.AddFromDirectory(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	links := make([]pb.ArchiveLink, 0, len(hashes))
+	for name, hash := range hashes {
+		links = append(links, pb.ArchiveLink{Key: archiveKey(dir, name), Hash: hash})
+	}
+	root, err = c.AddToArchive(root, links...)
+	if err != nil {
+		return nil, "", err
+	}
+	return hashes, root, nil
+}
+
+// archiveKey returns the key of the file with the given name in dir
+func archiveKey(dir, name string) string {
+	return filepath.ToSlash(filepath.Join(dir, name))
+}
+
+// GetFromArchive returns the link to the email stored under key
+func (c *Converter) GetFromArchive(root, key string) (*pb.ArchiveLink, error) {
+	hash := root
+	for depth := 0; depth < sha256.Size; depth++ {
+		node, err := c.getArchiveNode(hash)
+		if err != nil {
+			return nil, err
+		}
+		slot := findArchiveSlot(node, archiveIndex(key, depth))
+		if slot == nil {
+			return nil, ErrNotInArchive
+		}
+		if slot.ChildHash != "" {
+			hash = slot.ChildHash
+			continue
+		}
+		i := sort.Search(len(slot.Links), func(i int) bool { return slot.Links[i].Key >= key })
+		if i == len(slot.Links) || slot.Links[i].Key != key {
+			return nil, ErrNotInArchive
+		}
+		return &slot.Links[i], nil
+	}
+	return nil, ErrNotInArchive
+}
+
+// WalkArchive calls fn with every email linked from the archive
+func (c *Converter) WalkArchive(root string, fn func(link pb.ArchiveLink) error) error {
+	return c.walkArchive(root, func(string) {}, fn)
+}
+
+// CalculateArchiveSize calculates the size of an archive, including
+// its nodes and every email linked from it
+func (c *Converter) CalculateArchiveSize(root string) (int64, error) {
+	var nodes, hashes, chunkedHashes []string
+	err := c.walkArchive(root, func(hash string) {
+		nodes = append(nodes, hash)
+	}, func(link pb.ArchiveLink) error {
+		if link.Chunked {
+			chunkedHashes = append(chunkedHashes, link.Hash)
+		} else {
+			hashes = append(hashes, link.Hash)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, hash := range nodes {
+		resp, err := c.xclient.Dag(c.ctx, &xpb.DagRequest{
+			RequestType: xpb.DAGREQTYPE_DAG_STAT,
+			Hash:        hash,
+		})
+		if err != nil {
+			return 0, err
+		}
+		size += resp.GetNodeStats()[hash].GetCumulativeSize()
+	}
+	if len(hashes) > 0 {
+		emailSize, err := c.CalculateEmailSize(false, hashes...)
+		if err != nil {
+			return 0, err
+		}
+		size += emailSize
+	}
+	if len(chunkedHashes) > 0 {
+		emailSize, err := c.CalculateChunkedEmailSize(chunkedHashes...)
+		if err != nil {
+			return 0, err
+		}
+		size += emailSize
+	}
+	return size, nil
+}
+
+// walkArchive calls nodeFn with the hash of every node in the archive, and
+// linkFn with every email linked from them
+func (c *Converter) walkArchive(hash string, nodeFn func(hash string), linkFn func(link pb.ArchiveLink) error) error {
+	node, err := c.getArchiveNode(hash)
+	if err != nil {
+		return err
+	}
+	nodeFn(hash)
+	for _, slot := range node.Slots {
+		if slot.ChildHash != "" {
+			if err := c.walkArchive(slot.ChildHash, nodeFn, linkFn); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, link := range slot.Links {
+			if err := linkFn(link); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addToArchiveNode adds links to a node at depth, storing any child nodes it
+// changes, and returns the number of keys which were not already in the node
+func addToArchiveNode(store archiveNodeStore, node *pb.ArchiveNode, depth int, links []pb.ArchiveLink) (int, error) {
+	groups := make(map[uint32][]pb.ArchiveLink)
+	for _, link := range links {
+		index := archiveIndex(link.Key, depth)
+		groups[index] = append(groups[index], link)
+	}
+	var added int
+	for index, group := range groups {
+		slot := insertArchiveSlot(node, index)
+		if slot.ChildHash != "" {
+			child, err := store.getArchiveNode(slot.ChildHash)
+			if err != nil {
+				return 0, err
+			}
+			childAdded, err := addToArchiveNode(store, child, depth+1, group)
+			if err != nil {
+				return 0, err
+			}
+			if slot.ChildHash, err = store.putArchiveNode(child); err != nil {
+				return 0, err
+			}
+			added += childAdded
+			continue
+		}
+		before := len(slot.Links)
+		slot.Links = mergeArchiveLinks(slot.Links, group)
+		added += len(slot.Links) - before
+		// the last level can't be split, as every byte of the digest is used
+		if len(slot.Links) <= maxArchiveBucketSize || depth+1 == sha256.Size {
+			continue
+		}
+		child := new(pb.ArchiveNode)
+		if _, err := addToArchiveNode(store, child, depth+1, slot.Links); err != nil {
+			return 0, err
+		}
+		childHash, err := store.putArchiveNode(child)
+		if err != nil {
+			return 0, err
+		}
+		slot.ChildHash, slot.Links = childHash, nil
+	}
+	node.Count += uint64(added)
+	return added, nil
+}
+
+// findArchiveSlot returns the slot at index, or nil if it is empty
+func findArchiveSlot(node *pb.ArchiveNode, index uint32) *pb.ArchiveSlot {
+	i := sort.Search(len(node.Slots), func(i int) bool { return node.Slots[i].Index >= index })
+	if i == len(node.Slots) || node.Slots[i].Index != index {
+		return nil
+	}
+	return &node.Slots[i]
+}
+
+// insertArchiveSlot returns the slot at index, adding it if it is empty
+func insertArchiveSlot(node *pb.ArchiveNode, index uint32) *pb.ArchiveSlot {
+	i := sort.Search(len(node.Slots), func(i int) bool { return node.Slots[i].Index >= index })
+	if i == len(node.Slots) || node.Slots[i].Index != index {
+		node.Slots = append(node.Slots, pb.ArchiveSlot{})
+		copy(node.Slots[i+1:], node.Slots[i:])
+		node.Slots[i] = pb.ArchiveSlot{Index: index}
+	}
+	return &node.Slots[i]
+}
+
+// mergeArchiveLinks adds links to the sorted existing links, with later
+// links replacing earlier ones under the same key
+func mergeArchiveLinks(existing, links []pb.ArchiveLink) []pb.ArchiveLink {
+	byKey := make(map[string]pb.ArchiveLink, len(existing)+len(links))
+	for _, link := range existing {
+		byKey[link.Key] = link
+	}
+	for _, link := range links {
+		byKey[link.Key] = link
+	}
+	merged := make([]pb.ArchiveLink, 0, len(byKey))
+	for _, link := range byKey {
+		merged = append(merged, link)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Key < merged[j].Key })
+	return merged
+}
+
+// getArchiveNode returns the archive node stored at hash
+func (c *Converter) getArchiveNode(hash string) (*pb.ArchiveNode, error) {
+	data, err := c.getBytes(hash)
+	if err != nil {
+		return nil, err
+	}
+	node := new(pb.ArchiveNode)
+	if err := node.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(node.Version); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// putArchiveNode stores an archive node, returning its hash
+func (c *Converter) putArchiveNode(node *pb.ArchiveNode) (string, error) {
+	node.Version = compatibleVersion
+	data, err := node.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return c.putBytes(data)
+}
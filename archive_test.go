@@ -0,0 +1,130 @@
+package ipldeml
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestMergeArchiveLinks(t *testing.T) {
+	existing := []pb.ArchiveLink{{Key: "a", Hash: "1"}, {Key: "c", Hash: "3"}}
+	merged := mergeArchiveLinks(existing, []pb.ArchiveLink{
+		{Key: "b", Hash: "2"}, {Key: "c", Hash: "4"}, {Key: "b", Hash: "5"},
+	})
+	want := []pb.ArchiveLink{{Key: "a", Hash: "1"}, {Key: "b", Hash: "5"}, {Key: "c", Hash: "4"}}
+	if len(merged) != len(want) {
+		t.Fatal("bad merged links", merged)
+	}
+	for i := range want {
+		if !proto.Equal(&merged[i], &want[i]) {
+			t.Fatal("bad merged links", merged)
+		}
+	}
+}
+
+func TestArchiveSlots(t *testing.T) {
+	node := new(pb.ArchiveNode)
+	for _, index := range []uint32{5, 1, 9, 5, 3} {
+		insertArchiveSlot(node, index).Links = append(insertArchiveSlot(node, index).Links, pb.ArchiveLink{})
+	}
+	var indexes []uint32
+	for _, slot := range node.Slots {
+		indexes = append(indexes, slot.Index)
+	}
+	if len(indexes) != 4 || indexes[0] != 1 || indexes[1] != 3 || indexes[2] != 5 || indexes[3] != 9 {
+		t.Fatal("slots not sorted", indexes)
+	}
+	if slot := findArchiveSlot(node, 5); slot == nil || len(slot.Links) != 2 {
+		t.Fatal("bad slot", slot)
+	}
+	if slot := findArchiveSlot(node, 4); slot != nil {
+		t.Fatal("found empty slot", slot)
+	}
+}
+
+// memoryArchiveStore keeps archive nodes in memory, keyed by their digest
+type memoryArchiveStore map[string][]byte
+
+func (s memoryArchiveStore) getArchiveNode(hash string) (*pb.ArchiveNode, error) {
+	node := new(pb.ArchiveNode)
+	if err := node.Unmarshal(s[hash]); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (s memoryArchiveStore) putArchiveNode(node *pb.ArchiveNode) (string, error) {
+	data, err := node.Marshal()
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(data)
+	hash := hex.EncodeToString(digest[:])
+	s[hash] = data
+	return hash, nil
+}
+
+// testArchiveLinks returns n links whose keys all fall in the same slot of
+// the root node
+func testArchiveLinks(n int) []pb.ArchiveLink {
+	var links []pb.ArchiveLink
+	for i := 0; len(links) < n; i++ {
+		key := "email-" + strconv.Itoa(i) + ".eml"
+		if archiveIndex(key, 0) == 0 {
+			links = append(links, pb.ArchiveLink{Key: key, Hash: strconv.Itoa(i)})
+		}
+	}
+	return links
+}
+
+func TestAddToArchiveNodeSplit(t *testing.T) {
+	store := memoryArchiveStore{}
+	links := testArchiveLinks(maxArchiveBucketSize + 1)
+	node := new(pb.ArchiveNode)
+	added, err := addToArchiveNode(store, node, 0, links[:maxArchiveBucketSize])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != maxArchiveBucketSize || len(node.Slots) != 1 || len(node.Slots[0].Links) != maxArchiveBucketSize {
+		t.Fatal("full slot was split", node)
+	}
+	if added, err = addToArchiveNode(store, node, 0, links[maxArchiveBucketSize:]); err != nil {
+		t.Fatal(err)
+	}
+	slot := node.Slots[0]
+	if added != 1 || node.Count != uint64(len(links)) || slot.ChildHash == "" || len(slot.Links) != 0 {
+		t.Fatal("slot not split", node)
+	}
+	child, err := store.getArchiveNode(slot.ChildHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if child.Count != uint64(len(links)) {
+		t.Fatal("bad child count", child.Count)
+	}
+	var count int
+	for _, slot := range child.Slots {
+		for _, link := range slot.Links {
+			if archiveIndex(link.Key, 1) != slot.Index {
+				t.Fatal("link in wrong slot", link.Key, slot.Index)
+			}
+			count++
+		}
+	}
+	if count != len(links) {
+		t.Fatal("links lost in split", count)
+	}
+}
+
+func TestArchiveKey(t *testing.T) {
+	if a, b := archiveKey("samples/generated/10k", "email-0.eml"), archiveKey("samples/generated/more/", "email-0.eml"); a == b {
+		t.Fatal("same named files share a key", a)
+	}
+	if key := archiveKey("./samples", "sample1.eml"); key != "samples/sample1.eml" {
+		t.Fatal("bad key", key)
+	}
+}
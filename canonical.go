@@ -0,0 +1,51 @@
+package ipldeml
+
+import (
+	"reflect"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// canonicalizeEmail replaces empty slices and maps in the email with nil, so
+// an email returned by Convert matches the same email after a round trip
+// through its serialized form
+func canonicalizeEmail(email *pb.Email) {
+	canonicalize(reflect.ValueOf(email).Elem())
+}
+
+// canonicalize walks v, replacing empty slices and maps with nil
+func canonicalize(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			canonicalize(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				canonicalize(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if v.Len() == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			canonicalize(v.Index(i))
+		}
+	case reflect.Map:
+		if v.Len() == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		// map values can't be set in place, so replace them
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(iter.Value().Type()).Elem()
+			value.Set(iter.Value())
+			canonicalize(value)
+			v.SetMapIndex(iter.Key(), value)
+		}
+	}
+}
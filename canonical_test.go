@@ -0,0 +1,50 @@
+package ipldeml
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+func TestCanonicalizeEmail(t *testing.T) {
+	converter := NewConverter(context.Background(), nil)
+	// samples without files, which can be converted without storing anything
+	for _, file := range []string{"samples/sample1.eml", "samples/sample8.eml"} {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var encoded [][]byte
+		for i := 0; i < 2; i++ {
+			email, err := converter.Convert(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// marshal repeatedly as map iteration order is random
+			for j := 0; j < 10; j++ {
+				out, err := email.Marshal()
+				if err != nil {
+					t.Fatal(err)
+				}
+				encoded = append(encoded, out)
+			}
+			decoded := new(pb.Email)
+			if err := decoded.Unmarshal(encoded[0]); err != nil {
+				t.Fatal(err)
+			}
+			normalizeEmail(decoded)
+			if !reflect.DeepEqual(email, decoded) {
+				t.Fatalf("%s differs after a round trip", file)
+			}
+		}
+		for _, out := range encoded {
+			if !bytes.Equal(out, encoded[0]) {
+				t.Fatalf("%s encoded differently", file)
+			}
+		}
+	}
+}
@@ -2,15 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/RTradeLtd/go-temporalx-sdk/client"
 	ipldeml "github.com/RTradeLtd/ipld-eml"
 	"github.com/RTradeLtd/ipld-eml/analysis"
+	"github.com/RTradeLtd/ipld-eml/pb"
 	"github.com/urfave/cli/v2"
 )
 
@@ -73,6 +76,35 @@ func main() {
 			Usage:       "run specialized benchmark tool, calculating space savings",
 			Description: "calculates the total deduplicated size for the given emails",
 			Action: func(c *cli.Context) error {
+				if c.String("html.dir") != "" {
+					report, err := analysis.HTMLChunkSavings(c.String("html.dir"))
+					if err != nil {
+						return err
+					}
+					fmt.Println("emails with html bodies: ", report.Emails)
+					fmt.Println("total html size: ", report.TotalSize)
+					fmt.Println("deduplicated size storing bodies whole: ", report.WholeSize)
+					fmt.Println("deduplicated size chunking bodies: ", report.ChunkedSize)
+					fmt.Println("of which left inline: ", report.InlineSize)
+					if report.WholeSize > 0 {
+						fmt.Printf("space saved by chunking: %.2f%%\n", 100*float64(report.WholeSize-report.ChunkedSize)/float64(report.WholeSize))
+					}
+					return nil
+				}
+				if c.String("headers.dir") != "" {
+					report, err := analysis.HeaderTemplateSavings(c.String("headers.dir"))
+					if err != nil {
+						return err
+					}
+					fmt.Println("emails: ", report.Emails)
+					fmt.Println("unique header templates: ", report.Templates)
+					fmt.Println("total header size: ", report.TotalSize)
+					fmt.Println("header size sharing templates: ", report.SharedSize)
+					if report.TotalSize > 0 {
+						fmt.Printf("space saved by sharing templates: %.2f%%\n", 100*float64(report.TotalSize-report.SharedSize)/float64(report.TotalSize))
+					}
+					return nil
+				}
 				cl, err := client.NewClient(client.Opts{
 					ListenAddress: c.String("endpoint"),
 					Insecure:      c.Bool("insecure"),
@@ -80,6 +112,14 @@ func main() {
 				if err != nil {
 					return err
 				}
+				if c.String("archive.root") != "" {
+					size, err := ipldeml.NewConverter(ctx, cl).CalculateArchiveSize(c.String("archive.root"))
+					if err != nil {
+						return err
+					}
+					fmt.Println("total deduplicated archive size: ", size)
+					return nil
+				}
 				contents, err := ioutil.ReadFile(c.String("input.file"))
 				if err != nil {
 					return err
@@ -108,6 +148,18 @@ func main() {
 					Usage: "file to get hash information from",
 					Value: "converted_results.txt",
 				},
+				&cli.StringFlag{
+					Name:  "html.dir",
+					Usage: "directory of emails to report html chunking savings for, instead of calculating stored sizes",
+				},
+				&cli.StringFlag{
+					Name:  "headers.dir",
+					Usage: "directory of emails to report header template savings for, instead of calculating stored sizes",
+				},
+				&cli.StringFlag{
+					Name:  "archive.root",
+					Usage: "root of an archive to calculate the size of, instead of reading hashes from the input file",
+				},
 			},
 		},
 		{
@@ -132,11 +184,51 @@ func main() {
 						numFiles++
 					}
 				}
-				converter := ipldeml.NewConverter(ctx, cl)
-				res, err := converter.AddFromDirectory(c.String("email.dir"))
+				opts := converterOpts(c)
+				if opts.DuplicatePolicy, err = duplicatePolicy(c.String("duplicates")); err != nil {
+					return err
+				}
+				if opts.ShareHeaders {
+					// share the header values repeated between the emails being converted
+					if opts.HeaderCounts, err = ipldeml.CountHeaders(c.String("email.dir")); err != nil {
+						return err
+					}
+				}
+				converter := ipldeml.NewConverterWithOpts(ctx, cl, opts)
+				if c.String("index.root") != "" {
+					if err := converter.LoadMessageIndex(c.String("index.root")); err != nil {
+						return err
+					}
+				}
+				var res map[string]string
+				if c.Bool("archive") {
+					var root string
+					res, root, err = converter.AddFromDirectoryToArchive(c.String("email.dir"), c.String("archive.root"))
+					if err != nil {
+						return err
+					}
+					fmt.Println("archive root: ", root)
+				} else {
+					res, err = converter.AddFromDirectory(c.String("email.dir"))
+					if err != nil {
+						return err
+					}
+				}
+				index, err := converter.PutMessageIndex()
 				if err != nil {
 					return err
 				}
+				fmt.Println("message index: ", index)
+				if duplicates := converter.Duplicates(); len(duplicates) > 0 {
+					fmt.Println("duplicates found: ", len(duplicates))
+					report := ""
+					for _, d := range duplicates {
+						report = fmt.Sprintf("%s%s\t%s\t%s\t%s\t%s\t%v\n", report, d.Kind, d.Source, d.MessageID, d.Hash, d.Original, d.Skipped)
+					}
+					if err := ioutil.WriteFile(c.String("duplicates.file"), []byte(report), os.FileMode(0642)); err != nil {
+						return err
+					}
+				}
 				formatted := ""
 				for name, hash := range res {
 					if !c.Bool("only.hash") {
@@ -147,13 +239,524 @@ func main() {
 				}
 				return ioutil.WriteFile(c.String("save.file"), []byte(formatted), os.FileMode(0642))
 			},
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				&cli.BoolFlag{
 					Name:    "only.hash",
 					Aliases: []string{"oh", "o"},
 					Usage:   "whether or not to only store hash information",
 					Value:   true,
 				},
+				&cli.BoolFlag{
+					Name:  "archive",
+					Usage: "add the emails to an archive keyed by their path, printing the archive root",
+				},
+				&cli.StringFlag{
+					Name:  "archive.root",
+					Usage: "root of an existing archive to add the emails to, instead of starting a new archive",
+				},
+				&cli.StringFlag{
+					Name:  "duplicates",
+					Usage: "what to do with emails whose message id was already added, one of store, skip or link",
+					Value: "store",
+				},
+				&cli.StringFlag{
+					Name:  "index.root",
+					Usage: "hash of a message index from an earlier conversion to check for duplicates against",
+				},
+				&cli.StringFlag{
+					Name:  "duplicates.file",
+					Usage: "file to save the tab separated report of duplicates found",
+					Value: "duplicates.txt",
+				},
+			}, converterFlags()...),
+		},
+		{
+			Name:  "migrate",
+			Usage: "rewrite stored emails with the current schema version",
+			Description: "reads hashes from the input file, rewriting each email with the current schema version, " +
+				"and saves the old -> new hash mapping. emails already stored with the current version keep their hash when migrated with the options they were converted with",
+			Action: func(c *cli.Context) error {
+				cl, err := client.NewClient(client.Opts{
+					ListenAddress: c.String("endpoint"),
+					Insecure:      c.Bool("insecure"),
+				})
+				if err != nil {
+					return err
+				}
+				converter := ipldeml.NewConverterWithOpts(ctx, cl, converterOpts(c))
+				migrate := converter.MigrateEmail
+				if c.Bool("chunked") {
+					migrate = converter.MigrateEmailChunked
+				}
+				return mapHashes(c.String("input.file"), c.String("mapping.file"), migrate)
+			},
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:  "input.file",
+					Usage: "file to get hashes of the emails to migrate from",
+					Value: "converted_results.txt",
+				},
+				&cli.StringFlag{
+					Name:  "mapping.file",
+					Usage: "file to save old -> new hash information",
+					Value: "migrated_results.txt",
+				},
+				&cli.BoolFlag{
+					Name:  "chunked",
+					Usage: "whether the emails are stored in the chunked format",
+				},
+			}, converterFlags()...),
+		},
+		{
+			Name:  "convert-format",
+			Usage: "copy stored emails between the unixfs and chunked formats",
+			Description: "reads hashes from the input file, copying each email to the format given by --to, " +
+				"verifies the copy decodes to the same email, and saves the old -> new hash mapping",
+			Action: func(c *cli.Context) error {
+				cl, err := client.NewClient(client.Opts{
+					ListenAddress: c.String("endpoint"),
+					Insecure:      c.Bool("insecure"),
+				})
+				if err != nil {
+					return err
+				}
+				converter := ipldeml.NewConverterWithOpts(ctx, cl, converterOpts(c))
+				switch c.String("to") {
+				case "chunked":
+					return mapHashes(c.String("input.file"), c.String("mapping.file"), converter.ConvertToChunked)
+				case "unixfs":
+					return mapHashes(c.String("input.file"), c.String("mapping.file"), converter.ConvertFromChunked)
+				default:
+					return errors.New("--to must be one of chunked or unixfs")
+				}
+			},
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:  "input.file",
+					Usage: "file to get hashes of the emails to copy from",
+					Value: "converted_results.txt",
+				},
+				&cli.StringFlag{
+					Name:  "mapping.file",
+					Usage: "file to save old -> new hash information",
+					Value: "format_results.txt",
+				},
+				&cli.StringFlag{
+					Name:  "to",
+					Usage: "format to copy emails to, one of chunked or unixfs",
+					Value: "chunked",
+				},
+			}, converterFlags()...),
+		},
+		{
+			Name:        "threads",
+			Usage:       "thread stored emails into conversations",
+			Description: "reads hashes from the input file, threading the emails by their references and subjects, storing each thread and listing them",
+			Action: func(c *cli.Context) error {
+				cl, err := client.NewClient(client.Opts{
+					ListenAddress: c.String("endpoint"),
+					Insecure:      c.Bool("insecure"),
+				})
+				if err != nil {
+					return err
+				}
+				hashes, err := readHashes(c.String("input.file"))
+				if err != nil {
+					return err
+				}
+				converter := ipldeml.NewConverter(ctx, cl)
+				threadHashes, err := converter.ThreadEmails(hashes...)
+				if err != nil {
+					return err
+				}
+				for _, hash := range threadHashes {
+					thread, err := converter.GetThread(hash)
+					if err != nil {
+						return err
+					}
+					fmt.Printf("thread: %s\tsubject: %s\tmessages: %v\n", hash, thread.Subject, thread.Count)
+					printThreadNode(thread.Root, 1)
+				}
+				return nil
+			},
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "input.file",
+					Usage: "file to get hashes of the emails to thread from",
+					Value: "converted_results.txt",
+				},
+			},
+		},
+		{
+			Name:        "summaries",
+			Usage:       "list stored emails from their summaries",
+			Description: "reads hashes from the input file, listing each email from its summary without fetching its bodies and attachments",
+			Action: func(c *cli.Context) error {
+				cl, err := client.NewClient(client.Opts{
+					ListenAddress: c.String("endpoint"),
+					Insecure:      c.Bool("insecure"),
+				})
+				if err != nil {
+					return err
+				}
+				hashes, err := readHashes(c.String("input.file"))
+				if err != nil {
+					return err
+				}
+				converter := ipldeml.NewConverter(ctx, cl)
+				list := converter.ListSummaries
+				if c.Bool("chunked") {
+					list = converter.ListSummariesChunked
+				}
+				summaries, err := list(hashes...)
+				if err != nil {
+					return err
+				}
+				for i, summary := range summaries {
+					var from string
+					if len(summary.From) > 0 {
+						from = summary.From[0].Address
+					}
+					fmt.Printf("%s\tdate: %s\tfrom: %s\tsubject: %s\tattachments: %v\tsize: %v\n\t%s\n",
+						hashes[i], summary.Date.Format(time.RFC3339), from, summary.Subject, summary.Attachments, summary.MessageSize, summary.Snippet)
+				}
+				return nil
+			},
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "input.file",
+					Usage: "file to get hashes of the emails to list from",
+					Value: "converted_results.txt",
+				},
+				&cli.BoolFlag{
+					Name:  "chunked",
+					Usage: "whether the emails are stored in the chunked format",
+				},
+			},
+		},
+		{
+			Name:        "index",
+			Usage:       "add stored emails to a full text search index",
+			Description: "reads hashes from the input file, adding the emails to the search index and printing its new root",
+			Action: func(c *cli.Context) error {
+				cl, err := client.NewClient(client.Opts{
+					ListenAddress: c.String("endpoint"),
+					Insecure:      c.Bool("insecure"),
+				})
+				if err != nil {
+					return err
+				}
+				hashes, err := readHashes(c.String("input.file"))
+				if err != nil {
+					return err
+				}
+				root, err := ipldeml.NewConverter(ctx, cl).IndexEmails(c.String("index.root"), hashes...)
+				if err != nil {
+					return err
+				}
+				fmt.Println("search index: ", root)
+				return nil
+			},
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "input.file",
+					Usage: "file to get hashes of the emails to index from",
+					Value: "converted_results.txt",
+				},
+				&cli.StringFlag{
+					Name:  "index.root",
+					Usage: "root of an existing search index to add the emails to, instead of starting a new index",
+				},
+			},
+		},
+		{
+			Name:        "search",
+			Usage:       "search the emails in a full text search index",
+			ArgsUsage:   "<query>",
+			Description: "lists the emails containing every term of the query, which may be prefixed with subject:, body:, from: or to:",
+			Action: func(c *cli.Context) error {
+				cl, err := client.NewClient(client.Opts{
+					ListenAddress: c.String("endpoint"),
+					Insecure:      c.Bool("insecure"),
+				})
+				if err != nil {
+					return err
+				}
+				results, err := ipldeml.NewConverter(ctx, cl).Search(c.String("index.root"), strings.Join(c.Args().Slice(), " "))
+				if err != nil {
+					return err
+				}
+				for _, result := range results {
+					fmt.Printf("%s\tscore: %v\n", result.Hash, result.Score)
+				}
+				return nil
+			},
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "index.root",
+					Usage:    "root of the search index to search",
+					Required: true,
+				},
+			},
+		},
+		{
+			Name:  "metadata",
+			Usage: "query stored emails by their metadata",
+			Subcommands: cli.Commands{
+				{
+					Name:        "index",
+					Usage:       "add stored emails to a metadata index",
+					Description: "reads hashes from the input file, adding the emails to the metadata index and printing its new root",
+					Action: func(c *cli.Context) error {
+						cl, err := client.NewClient(client.Opts{
+							ListenAddress: c.String("endpoint"),
+							Insecure:      c.Bool("insecure"),
+						})
+						if err != nil {
+							return err
+						}
+						hashes, err := readHashes(c.String("input.file"))
+						if err != nil {
+							return err
+						}
+						root, err := ipldeml.NewConverter(ctx, cl).IndexMetadata(c.String("metadata.root"), hashes...)
+						if err != nil {
+							return err
+						}
+						fmt.Println("metadata index: ", root)
+						return nil
+					},
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "input.file",
+							Usage: "file to get hashes of the emails to index from",
+							Value: "converted_results.txt",
+						},
+						&cli.StringFlag{
+							Name:  "metadata.root",
+							Usage: "root of an existing metadata index to add the emails to, instead of starting a new index",
+						},
+					},
+				},
+				{
+					Name:  "query",
+					Usage: "list the emails in a metadata index matching every given flag",
+					Action: func(c *cli.Context) error {
+						query := ipldeml.MetadataQuery{
+							FromDomain:     c.String("from.domain"),
+							ToDomain:       c.String("to.domain"),
+							CcDomain:       c.String("cc.domain"),
+							HasAttachments: c.Bool("has.attachments"),
+							AttachmentType: c.String("attachment.type"),
+							MinSize:        c.Uint64("min.size"),
+							MaxSize:        c.Uint64("max.size"),
+							Headers:        c.StringSlice("header"),
+						}
+						var err error
+						if query.After, err = parseDateFlag(c.String("after")); err != nil {
+							return err
+						}
+						if query.Before, err = parseDateFlag(c.String("before")); err != nil {
+							return err
+						}
+						cl, err := client.NewClient(client.Opts{
+							ListenAddress: c.String("endpoint"),
+							Insecure:      c.Bool("insecure"),
+						})
+						if err != nil {
+							return err
+						}
+						records, err := ipldeml.NewConverter(ctx, cl).QueryMetadata(c.String("metadata.root"), query)
+						if err != nil {
+							return err
+						}
+						for _, record := range records {
+							fmt.Printf("%s\tdate: %s\tsize: %v\tattachments: %v\n", record.Hash, record.Date.Format(time.RFC3339), record.MessageSize, record.Attachments)
+						}
+						return nil
+					},
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:     "metadata.root",
+							Usage:    "root of the metadata index to query",
+							Required: true,
+						},
+						&cli.StringFlag{
+							Name:  "from.domain",
+							Usage: "domain of the sender, from or reply-to addresses, including subdomains",
+						},
+						&cli.StringFlag{
+							Name:  "to.domain",
+							Usage: "domain of the to addresses, including subdomains",
+						},
+						&cli.StringFlag{
+							Name:  "cc.domain",
+							Usage: "domain of the cc or bcc addresses, including subdomains",
+						},
+						&cli.StringFlag{
+							Name:  "after",
+							Usage: "only list emails sent on or after the date, as YYYY-MM-DD",
+						},
+						&cli.StringFlag{
+							Name:  "before",
+							Usage: "only list emails sent before the date, as YYYY-MM-DD",
+						},
+						&cli.BoolFlag{
+							Name:  "has.attachments",
+							Usage: "only list emails with attachments",
+						},
+						&cli.StringFlag{
+							Name:  "attachment.type",
+							Usage: "content type of an attachment or embedded file, or a major type such as image/",
+						},
+						&cli.Uint64Flag{
+							Name:  "min.size",
+							Usage: "minimum size of the bodies and files of the email in bytes",
+						},
+						&cli.Uint64Flag{
+							Name:  "max.size",
+							Usage: "maximum size of the bodies and files of the email in bytes",
+						},
+						&cli.StringSliceFlag{
+							Name:  "header",
+							Usage: "name of a header which must be present, may be repeated",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:  "dates",
+			Usage: "list stored emails by date",
+			Subcommands: cli.Commands{
+				{
+					Name:        "index",
+					Usage:       "add stored emails to a date index",
+					Description: "reads hashes from the input file, adding the emails to the date index and printing its new root",
+					Action: func(c *cli.Context) error {
+						cl, err := client.NewClient(client.Opts{
+							ListenAddress: c.String("endpoint"),
+							Insecure:      c.Bool("insecure"),
+						})
+						if err != nil {
+							return err
+						}
+						hashes, err := readHashes(c.String("input.file"))
+						if err != nil {
+							return err
+						}
+						root, err := ipldeml.NewConverter(ctx, cl).IndexDates(c.String("dates.root"), hashes...)
+						if err != nil {
+							return err
+						}
+						fmt.Println("date index: ", root)
+						return nil
+					},
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "input.file",
+							Usage: "file to get hashes of the emails to index from",
+							Value: "converted_results.txt",
+						},
+						&cli.StringFlag{
+							Name:  "dates.root",
+							Usage: "root of an existing date index to add the emails to, instead of starting a new index",
+						},
+					},
+				},
+				{
+					Name:  "list",
+					Usage: "list the emails in a date index sent in a date range",
+					Action: func(c *cli.Context) error {
+						after, err := parseDateFlag(c.String("after"))
+						if err != nil {
+							return err
+						}
+						before, err := parseDateFlag(c.String("before"))
+						if err != nil {
+							return err
+						}
+						cl, err := client.NewClient(client.Opts{
+							ListenAddress: c.String("endpoint"),
+							Insecure:      c.Bool("insecure"),
+						})
+						if err != nil {
+							return err
+						}
+						converter := ipldeml.NewConverter(ctx, cl)
+						return converter.WalkDateRange(c.String("dates.root"), after, before, func(entry pb.DateEntry) error {
+							fmt.Printf("%s\tdate: %s\tmessage id: %s\n", entry.Hash, entry.Date.Format(time.RFC3339), entry.MessageID)
+							return nil
+						})
+					},
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:     "dates.root",
+							Usage:    "root of the date index to list",
+							Required: true,
+						},
+						&cli.StringFlag{
+							Name:  "after",
+							Usage: "only list emails sent on or after the date, as YYYY-MM-DD",
+						},
+						&cli.StringFlag{
+							Name:  "before",
+							Usage: "only list emails sent before the date, as YYYY-MM-DD",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:  "mailbox",
+			Usage: "archive folder trees such as maildirs as mailboxes",
+			Subcommands: cli.Commands{
+				{
+					Name:  "import",
+					Usage: "convert every email in the email directory tree, storing each directory as a folder",
+					Action: func(c *cli.Context) error {
+						cl, err := client.NewClient(client.Opts{
+							ListenAddress: c.String("endpoint"),
+							Insecure:      c.Bool("insecure"),
+						})
+						if err != nil {
+							return err
+						}
+						converter := ipldeml.NewConverterWithOpts(ctx, cl, converterOpts(c))
+						root, err := converter.ImportMailbox(c.String("email.dir"))
+						if err != nil {
+							return err
+						}
+						fmt.Println("mailbox root: ", root)
+						return nil
+					},
+					Flags: converterFlags(),
+				},
+				{
+					Name:  "list",
+					Usage: "list the folders of a mailbox",
+					Action: func(c *cli.Context) error {
+						cl, err := client.NewClient(client.Opts{
+							ListenAddress: c.String("endpoint"),
+							Insecure:      c.Bool("insecure"),
+						})
+						if err != nil {
+							return err
+						}
+						converter := ipldeml.NewConverter(ctx, cl)
+						return converter.WalkFolders(c.String("mailbox.root"), func(path []string, folder *pb.Folder) error {
+							fmt.Printf("/%s\t%v messages\n", strings.Join(path, "/"), len(folder.Entries))
+							return nil
+						})
+					},
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:     "mailbox.root",
+							Usage:    "hash of the root folder of the mailbox",
+							Required: true,
+						},
+					},
+				},
 			},
 		},
 		{
@@ -176,3 +779,120 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// converterFlags returns the flags enabling optional converter behaviour
+func converterFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "normalize.addresses",
+			Usage: "store a normalized form of addresses alongside the original",
+		},
+		&cli.BoolFlag{
+			Name:  "dedupe.quotes",
+			Usage: "store quoted replies as shared objects",
+		},
+		&cli.BoolFlag{
+			Name:  "chunk.html",
+			Usage: "split html bodies at block elements, storing each block as a shared object",
+		},
+		&cli.BoolFlag{
+			Name:  "compact.headers",
+			Usage: "only store headers which repeat the structured fields once",
+		},
+		&cli.BoolFlag{
+			Name:  "share.headers",
+			Usage: "store headers commonly repeated between emails as shared template objects",
+		},
+		&cli.BoolFlag{
+			Name:  "split.envelope",
+			Usage: "store the parts of an email shared by every delivered copy once, linked from a small envelope per copy",
+		},
+		&cli.BoolFlag{
+			Name:  "delta.replies",
+			Usage: "store replies to emails already converted as a binary delta against the email they reply to",
+		},
+	}
+}
+
+// converterOpts returns the converter options set by converterFlags
+func converterOpts(c *cli.Context) ipldeml.Opts {
+	return ipldeml.Opts{
+		NormalizeAddresses: c.Bool("normalize.addresses"),
+		DedupeQuotes:       c.Bool("dedupe.quotes"),
+		ChunkHTML:          c.Bool("chunk.html"),
+		CompactHeaders:     c.Bool("compact.headers"),
+		ShareHeaders:       c.Bool("share.headers"),
+		SplitEnvelope:      c.Bool("split.envelope"),
+		DeltaReplies:       c.Bool("delta.replies"),
+	}
+}
+
+// duplicatePolicy returns the duplicate policy with the given name
+func duplicatePolicy(name string) (ipldeml.DuplicatePolicy, error) {
+	switch name {
+	case "store":
+		return ipldeml.StoreDuplicates, nil
+	case "skip":
+		return ipldeml.SkipDuplicates, nil
+	case "link":
+		return ipldeml.LinkVariants, nil
+	}
+	return 0, fmt.Errorf("unknown duplicate policy %q", name)
+}
+
+// parseDateFlag parses a YYYY-MM-DD date flag as midnight utc, returning
+// the zero time for an empty flag
+func parseDateFlag(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// readHashes returns the hashes in the input file, one per line
+func readHashes(inputFile string) ([]string, error) {
+	contents, err := ioutil.ReadFile(inputFile)
+	if err != nil {
+		return nil, err
+	}
+	var hashes []string
+	for _, hash := range strings.Split(string(contents), "\n") {
+		if hash != "" {
+			hashes = append(hashes, hash)
+		}
+	}
+	return hashes, nil
+}
+
+// mapHashes calls fn with every hash in the input file, saving the
+// tab separated old -> new hash mapping to the mapping file
+func mapHashes(inputFile, mappingFile string, fn func(hash string) (string, error)) error {
+	contents, err := ioutil.ReadFile(inputFile)
+	if err != nil {
+		return err
+	}
+	formatted := ""
+	for _, hash := range strings.Split(string(contents), "\n") {
+		if hash == "" {
+			continue
+		}
+		newHash, err := fn(hash)
+		if err != nil {
+			return err
+		}
+		formatted = fmt.Sprintf("%s%s\t%s\n", formatted, hash, newHash)
+	}
+	return ioutil.WriteFile(mappingFile, []byte(formatted), os.FileMode(0642))
+}
+
+// printThreadNode prints a thread node and its replies, indented by depth
+func printThreadNode(node pb.ThreadNode, depth int) {
+	hash := node.Hash
+	if hash == "" {
+		hash = "(not stored) <" + node.MessageID + ">"
+	}
+	fmt.Printf("%s%s\n", strings.Repeat("  ", depth), hash)
+	for _, child := range node.Children {
+		printThreadNode(child, depth+1)
+	}
+}
@@ -0,0 +1,145 @@
+package ipldeml
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains helpers for removing headers from the headers map which repeat
+// the structured fields, so each piece of information is only stored once
+
+// address styles, combined as flags, describing how an address header was written
+const (
+	// addresses without a name are written as <address>
+	addressStyleAngle int32 = 1 << iota
+	// names are written in quotes
+	addressStyleQuoted
+)
+
+// compactHeaderNames are the headers which can be rebuilt from the
+// structured fields, in the order they are tried
+var compactHeaderNames = []string{
+	"Subject", "Date", "Message-Id", "In-Reply-To", "References",
+	"Sender", "From", "Reply-To", "To", "Cc", "Bcc",
+}
+
+// compactHeaders removes the headers which rebuildHeader gives back exactly,
+// recording them so expandHeaders can restore them
+func compactHeaders(email *pb.Email) {
+	// copy so the callers headers are left as is
+	values := make(map[string]pb.Headers, len(email.Headers.Values))
+	for k, v := range email.Headers.Values {
+		values[k] = v
+	}
+	email.Headers.Values = values
+	for _, name := range compactHeaderNames {
+		header, ok := values[name]
+		if !ok || len(header.Values) != 1 {
+			continue
+		}
+		for style := int32(0); style <= addressStyleAngle|addressStyleQuoted; style++ {
+			if value, ok := rebuildHeader(email, name, style); ok && value == header.Values[0] {
+				delete(values, name)
+				email.ElidedHeaders = append(email.ElidedHeaders, pb.ElidedHeader{Name: name, Style: style})
+				break
+			}
+			if !isAddressHeader(name) {
+				break
+			}
+		}
+	}
+}
+
+// expandHeaders is the reverse of compactHeaders
+func expandHeaders(email *pb.Email) error {
+	if len(email.ElidedHeaders) == 0 {
+		return nil
+	}
+	if email.Headers.Values == nil {
+		email.Headers.Values = make(map[string]pb.Headers, len(email.ElidedHeaders))
+	}
+	for _, elided := range email.ElidedHeaders {
+		value, ok := rebuildHeader(email, elided.Name, elided.Style)
+		if !ok {
+			return errors.New("unable to restore header " + elided.Name)
+		}
+		email.Headers.Values[elided.Name] = pb.Headers{Values: []string{value}}
+	}
+	email.ElidedHeaders = nil
+	return nil
+}
+
+// rebuildHeader returns the value of a header from the structured fields,
+// returning false if there is nothing to rebuild it from
+func rebuildHeader(email *pb.Email, name string, style int32) (string, bool) {
+	addrs := email.Addresses
+	switch name {
+	case "Subject":
+		return email.Subject, true
+	case "Date":
+		return email.RawDate, email.RawDate != ""
+	case "Message-Id":
+		return "<" + email.MessageID + ">", email.MessageID != ""
+	case "In-Reply-To":
+		return formatMessageIDs(email.InReplyTo), len(email.InReplyTo) > 0
+	case "References":
+		return formatMessageIDs(email.References), len(email.References) > 0
+	case "Sender":
+		if addrs.Sender == nil {
+			return "", false
+		}
+		return formatAddresses([]pb.Address{*addrs.Sender}, style), true
+	case "From":
+		return formatAddresses(addrs.From, style), len(addrs.From) > 0
+	case "Reply-To":
+		return formatAddresses(addrs.ReplyTo, style), len(addrs.ReplyTo) > 0
+	case "To":
+		return formatAddresses(addrs.To, style), len(addrs.To) > 0
+	case "Cc":
+		return formatAddresses(addrs.Cc, style), len(addrs.Cc) > 0
+	case "Bcc":
+		return formatAddresses(addrs.Bcc, style), len(addrs.Bcc) > 0
+	}
+	return "", false
+}
+
+// isAddressHeader reports if the header holds addresses, whose formatting
+// is recorded by the style
+func isAddressHeader(name string) bool {
+	switch name {
+	case "Sender", "From", "Reply-To", "To", "Cc", "Bcc":
+		return true
+	}
+	return false
+}
+
+// formatMessageIDs formats message ids the way parsemail parses them
+func formatMessageIDs(ids []string) string {
+	formatted := make([]string, len(ids))
+	for i, id := range ids {
+		formatted[i] = "<" + id + ">"
+	}
+	return strings.Join(formatted, " ")
+}
+
+var quotedNameReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// formatAddresses formats an address list in the given style
+func formatAddresses(addrs []pb.Address, style int32) string {
+	formatted := make([]string, len(addrs))
+	for i, addr := range addrs {
+		switch {
+		case addr.Name == "" && style&addressStyleAngle == 0:
+			formatted[i] = addr.Address
+		case addr.Name == "":
+			formatted[i] = "<" + addr.Address + ">"
+		case style&addressStyleQuoted != 0:
+			formatted[i] = `"` + quotedNameReplacer.Replace(addr.Name) + `" <` + addr.Address + ">"
+		default:
+			formatted[i] = addr.Name + " <" + addr.Address + ">"
+		}
+	}
+	return strings.Join(formatted, ", ")
+}
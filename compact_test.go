@@ -0,0 +1,66 @@
+package ipldeml
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestCompactHeaders(t *testing.T) {
+	var (
+		converter = NewConverter(context.Background(), nil)
+		emls      []string
+	)
+	for _, file := range []string{"samples/sample1.eml", "samples/sample8.eml"} {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		emls = append(emls, string(data))
+	}
+	emls = append(emls,
+		"From: \"Doe, Jane\" <jane@example.com>\r\n"+
+			"Sender: <list@example.com>\r\n"+
+			"To: bob@example.com, Carol <carol@example.com>\r\n"+
+			"Cc: undisclosed-recipients:;\r\n"+
+			"Subject: =?utf-8?q?caf=C3=A9?=\r\n"+
+			"Date: Fri, 27 Mar 2020 14:48:17 +0100\r\n"+
+			"Message-ID: <abc@example.com>\r\n"+
+			"In-Reply-To: <parent@example.com>\r\n"+
+			"References: <root@example.com>\r\n <parent@example.com>\r\n"+
+			"Received: from a\r\nReceived: from b\r\n\r\nbody\r\n",
+	)
+	for _, eml := range emls {
+		email, err := converter.Convert(strings.NewReader(eml))
+		if err != nil {
+			t.Fatal(err)
+		}
+		compacted := *email
+		compactHeaders(&compacted)
+		if len(compacted.ElidedHeaders) == 0 {
+			t.Fatal("no headers were elided")
+		}
+		if len(email.ElidedHeaders) != 0 || len(email.Headers.Values) != len(compacted.Headers.Values)+len(compacted.ElidedHeaders) {
+			t.Fatal("bad headers after compacting")
+		}
+		data, err := compacted.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		restored := new(pb.Email)
+		if err := restored.Unmarshal(data); err != nil {
+			t.Fatal(err)
+		}
+		if err := expandHeaders(restored); err != nil {
+			t.Fatal(err)
+		}
+		normalizeEmail(restored)
+		if !proto.Equal(email, restored) {
+			t.Fatalf("headers not restored, got %v want %v", restored.Headers, email.Headers)
+		}
+	}
+}
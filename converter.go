@@ -6,7 +6,9 @@ import (
 	"errors"
 	"io"
 	"io/ioutil"
-	"os"
+	"net/mail"
+	"net/textproto"
+	"strings"
 
 	"github.com/DusanKasan/parsemail"
 	xpb "github.com/RTradeLtd/TxPB/v3/go"
@@ -19,13 +21,59 @@ import (
 type Converter struct {
 	ctx     context.Context
 	xclient *client.Client
+	opts    Opts
+	// message id index of the emails added with AddFromDirectory
+	index      map[string]*pb.MessageIndexEntry
+	duplicates []Duplicate
 }
 
-// NewConverter instantiates our new converter
+// Opts is used to enable optional behaviour of the converter
+type Opts struct {
+	// NormalizeAddresses stores a normalized form of every address
+	// alongside the original, for use in indexing
+	NormalizeAddresses bool
+	// DedupeQuotes stores the quoted regions of bodies as objects of their
+	// own, so the earlier messages quoted by replies are only stored once
+	DedupeQuotes bool
+	// ChunkHTML splits html bodies at block element boundaries, storing each
+	// block as an object of its own so shared template sections are stored once
+	ChunkHTML bool
+	// CompactHeaders removes headers which repeat the structured fields from
+	// the stored headers map, rebuilding them when the email is retrieved
+	CompactHeaders bool
+	// ShareHeaders stores the headers commonly repeated between emails, such
+	// as those added by the same mail system, as shared template objects
+	ShareHeaders bool
+	// HeaderCounts are the counts of the header values of the corpus being
+	// stored, so ShareHeaders only shares the values which repeat. without
+	// them the headers which usually differ between messages are left out
+	HeaderCounts HeaderCounts
+	// SplitEnvelope stores the parts of an email shared by every delivered
+	// copy as a core object, linked from a small envelope holding the trace
+	// headers of the copy, so each further copy only stores its envelope
+	SplitEnvelope bool
+	// DeltaReplies stores replies whose parent was already added as a binary
+	// delta against the parent, when that is less than half their size
+	DeltaReplies bool
+	// DuplicatePolicy decides what AddFromDirectory does with messages
+	// whose message id was already added
+	DuplicatePolicy DuplicatePolicy
+}
+
+// NewConverter instantiates our new converter. without a client emails can
+// only be converted, such as for analysing them, and the hashes of their
+// attachments and embedded files are left empty
 func NewConverter(ctx context.Context, xclient *client.Client) *Converter {
+	return NewConverterWithOpts(ctx, xclient, Opts{})
+}
+
+// NewConverterWithOpts instantiates our new converter with optional behaviour enabled
+func NewConverterWithOpts(ctx context.Context, xclient *client.Client, opts Opts) *Converter {
 	return &Converter{
 		ctx:     ctx,
 		xclient: xclient,
+		opts:    opts,
+		index:   make(map[string]*pb.MessageIndexEntry),
 	}
 }
 
@@ -50,18 +98,7 @@ func (c *Converter) AddFromDirectory(dir string) (map[string]string, error) {
 		if f.IsDir() {
 			continue
 		}
-		hash, err := func() (string, error) {
-			fh, err := os.Open(dir + "/" + f.Name())
-			if err != nil {
-				return "", err
-			}
-			defer fh.Close()
-			em, err := c.Convert(fh)
-			if err != nil {
-				return "", err
-			}
-			return c.PutEmail(em)
-		}()
+		hash, err := c.addFile(dir + "/" + f.Name())
 		if err != nil {
 			return nil, err
 		}
@@ -73,180 +110,270 @@ func (c *Converter) AddFromDirectory(dir string) (map[string]string, error) {
 	return hashes, nil
 }
 
+// addFile converts the email in the given file, uploading it to ipfs
+func (c *Converter) addFile(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return c.addMessage(path, data)
+}
+
 // GetEmail is a helper function to retrieve an email object
 // from ipfs, and return its protocol buffer type
 func (c *Converter) GetEmail(hash string) (*pb.Email, error) {
-	resp, err := c.xclient.DownloadFile(c.ctx, &xpb.DownloadRequest{
-		Hash: hash,
-	}, false)
+	data, err := c.getBytes(hash)
 	if err != nil {
 		return nil, err
 	}
-	email := new(pb.Email)
-	if err := email.Unmarshal(resp.Bytes()); err != nil {
+	return c.decodeEmail(data)
+}
+
+// GetEmailOriginalZone is like GetEmail, but returns the date values
+// in the timezone the email was originally sent from
+func (c *Converter) GetEmailOriginalZone(hash string) (*pb.Email, error) {
+	email, err := c.GetEmail(hash)
+	if err != nil {
 		return nil, err
 	}
-	// normalize time values
-	email.Date = email.Date.UTC()
-	if email.Resent != nil {
-		email.Resent.ResentDate = email.Resent.ResentDate.UTC()
+	email.Date = OriginalDate(email)
+	for i := range email.ResentBlocks {
+		email.ResentBlocks[i].ResentDate = OriginalResentDate(&email.ResentBlocks[i])
 	}
 	return email, nil
 }
 
 // PutEmail is a helper function to store an email objecto n ipfs
 func (c *Converter) PutEmail(email *pb.Email) (string, error) {
-	data, err := email.Marshal()
+	data, err := c.encodeEmail(email)
 	if err != nil {
 		return "", err
 	}
-	resp, err := c.xclient.UploadFile(c.ctx, bytes.NewReader(data), 0, nil, false)
-	if err != nil {
-		return "", err
-	}
-	return resp.GetHash(), nil
+	return c.putBytes(data)
 }
 
 // Convert takes a reader for an eml file, and returns the ipfs hash
 func (c *Converter) Convert(reader io.Reader) (*pb.Email, error) {
-	eml, err := parsemail.Parse(reader)
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	eml, err := parsemail.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	parts, err := readParts(msg.Header, msg.Body)
 	if err != nil {
 		return nil, err
 	}
 	email := &pb.Email{
 		Headers: pb.Header{
-			Values: make(map[string]pb.Headers, len(eml.Header)),
+			Values: make(map[string]pb.Headers, len(msg.Header)),
 		},
 		Attachments:   make([]pb.Attachment, len(eml.Attachments)),
 		EmbeddedFiles: make([]pb.EmbeddedFile, len(eml.EmbeddedFiles)),
-		// normalize time value
-		Date:       eml.Date.UTC(),
-		MessageID:  eml.MessageID,
-		InReplyTo:  eml.InReplyTo,
-		References: eml.References,
+		RawDate:       msg.Header.Get("Date"),
+		MessageID:     eml.MessageID,
+		InReplyTo:     eml.InReplyTo,
+		References:    eml.References,
+		Version:       EmailVersion,
 	}
-	// set header
-	for k, v := range eml.Header {
-		email.Headers.Values[k] = pb.Headers{Values: v}
+	// set header, decoding any encoded words
+	for k, v := range msg.Header {
+		values := make([]string, len(v))
+		for i, value := range v {
+			values[i] = decodeHeader(value)
+		}
+		email.Headers.Values[k] = pb.Headers{Values: values}
 	}
+	// set the date, normalized to utc with the original offset kept alongside
+	email.Date, email.DateOffset, email.DateInvalid = parseDate(email.RawDate)
 	// set subject
-	email.Subject = eml.Subject
+	email.Subject = decodeHeader(msg.Header.Get("Subject"))
 	// set the addresses
-	addrs := pb.Addresses{
-		From:    make([]pb.Address, len(eml.From)),
-		ReplyTo: make([]pb.Address, len(eml.ReplyTo)),
-		To:      make([]pb.Address, len(eml.To)),
-		Cc:      make([]pb.Address, len(eml.Cc)),
-		Bcc:     make([]pb.Address, len(eml.Bcc)),
-	}
-	if eml.Sender != nil {
-		addrs.Sender = &pb.Address{
-			Name:    eml.Sender.Name,
-			Address: eml.Sender.Address,
-		}
+	email.Addresses = parseAddresses(func(key string) string { return msg.Header.Get(key) }, "")
+	// set every block of resent fields
+	email.ResentBlocks = resentBlocks(readHeaderFields(data))
+	if c.opts.NormalizeAddresses {
+		forEachAddress(email, normalizeAddress)
 	}
-	for i, v := range eml.From {
-		addrs.From[i] = pb.Address{
-			Name:    v.Name,
-			Address: v.Address,
+	// set the bodies, decoding them to utf-8 from their declared charset
+	var (
+		text, html  body
+		attachParts []mimePart
+		embedParts  []mimePart
+	)
+	for _, part := range parts {
+		switch part.kind {
+		case partText:
+			text.add(part)
+		case partHTML:
+			html.add(part)
+		case partAttachment:
+			attachParts = append(attachParts, part)
+		case partEmbedded:
+			embedParts = append(embedParts, part)
 		}
 	}
-	for i, v := range eml.ReplyTo {
-		addrs.ReplyTo[i] = pb.Address{
-			Name:    v.Name,
-			Address: v.Address,
-		}
+	email.TextBody, email.TextBodyRaw = text.result()
+	email.TextCharset = text.charset
+	email.HtmlBody, email.HtmlBodyRaw = html.result()
+	email.HtmlCharset = html.charset
+	// prefer our own parts when they line up with parsemail, as they
+	// handle more charsets and carry the part headers
+	if len(attachParts) != len(eml.Attachments) {
+		attachParts = nil
 	}
-	for i, v := range eml.To {
-		addrs.To[i] = pb.Address{
-			Name:    v.Name,
-			Address: v.Address,
-		}
+	if len(embedParts) != len(eml.EmbeddedFiles) {
+		embedParts = nil
 	}
-	for i, v := range eml.Cc {
-		addrs.Cc[i] = pb.Address{
-			Name:    v.Name,
-			Address: v.Address,
+	for i, attach := range eml.Attachments {
+		data, err := ioutil.ReadAll(attach.Data)
+		if err != nil {
+			return nil, err
 		}
-	}
-	for i, v := range eml.Bcc {
-		addrs.Bcc[i] = pb.Address{
-			Name:    v.Name,
-			Address: v.Address,
+		var header textproto.MIMEHeader
+		fileName := attach.Filename
+		if attachParts != nil {
+			header = attachParts[i].header
+			fileName = partFileName(header)
 		}
-	}
-	email.Addresses = addrs
-	// only set this if we have 1 or more non-nil fields
-	if eml.ResentFrom != nil || eml.ResentTo != nil || eml.ResentCc != nil || eml.ResentBcc != nil {
-		var resent = &pb.Resent{
-			Addresses: pb.Addresses{
-				From: make([]pb.Address, len(eml.ResentFrom)),
-				To:   make([]pb.Address, len(eml.ResentTo)),
-				Cc:   make([]pb.Address, len(eml.ResentCc)),
-				Bcc:  make([]pb.Address, len(eml.ResentBcc)),
-			},
-			ResentMessageId: eml.ResentMessageID,
-			// normalize time value
-			ResentDate: eml.ResentDate.UTC(),
+		email.Attachments[i] = pb.Attachment{
+			FileName:    validUTF8(fileName),
+			ContentType: attach.ContentType,
+			Metadata:    fileMetadata(header, data),
 		}
-		for i, v := range eml.ResentFrom {
-			resent.Addresses.From[i] = pb.Address{
-				Name:    v.Name,
-				Address: v.Address,
+		if c.xclient != nil && strings.EqualFold(attach.ContentType, "message/rfc822") {
+			// store attached emails as emails of their own so they deduplicate
+			// against the same email stored on its own, falling back to storing
+			// the raw file if it can't be converted
+			if hash, err := c.convertAttachedEmail(data); err == nil {
+				email.Attachments[i].EmailHash = hash
+				continue
 			}
 		}
-		if eml.ResentSender != nil {
-			resent.Addresses.Sender = &pb.Address{
-				Name:    eml.ResentSender.Name,
-				Address: eml.ResentSender.Address,
-			}
+		if email.Attachments[i].DataHash, err = c.putFile(data); err != nil {
+			return nil, err
 		}
-		for i, v := range eml.ResentTo {
-			resent.Addresses.To[i] = pb.Address{
-				Name:    v.Name,
-				Address: v.Address,
-			}
+	}
+	for i, embed := range eml.EmbeddedFiles {
+		data, err := ioutil.ReadAll(embed.Data)
+		if err != nil {
+			return nil, err
 		}
-		for i, v := range eml.ResentCc {
-			resent.Addresses.Cc[i] = pb.Address{
-				Name:    v.Name,
-				Address: v.Address,
-			}
+		var header textproto.MIMEHeader
+		if embedParts != nil {
+			header = embedParts[i].header
+		}
+		hash, err := c.putFile(data)
+		if err != nil {
+			return nil, err
+		}
+		email.EmbeddedFiles[i] = pb.EmbeddedFile{
+			ContentId:   embed.CID,
+			ContentType: embed.ContentType,
+			DataHash:    hash,
+			FileName:    partFileName(header),
+			Metadata:    fileMetadata(header, data),
+		}
+	}
+	// move data uris in the html body into embedded files
+	if err := c.extractDataURIs(email); err != nil {
+		return nil, err
+	}
+	canonicalizeEmail(email)
+	return email, nil
+}
+
+// convertAttachedEmail converts and stores an email attached to another email
+func (c *Converter) convertAttachedEmail(data []byte) (string, error) {
+	email, err := c.Convert(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	return c.PutEmail(email)
+}
+
+// linkedHashes returns the hashes of every object linked to by the stored email
+// which are not already in seen, walking any attached emails as well
+func (c *Converter) linkedHashes(email *pb.Email, seen map[string]bool) ([]string, error) {
+	var hashes []string
+	add := func(hash string) bool {
+		if hash == "" || seen[hash] {
+			return false
 		}
-		for i, v := range eml.ResentBcc {
-			resent.Addresses.Bcc[i] = pb.Address{
-				Name:    v.Name,
-				Address: v.Address,
+		seen[hash] = true
+		hashes = append(hashes, hash)
+		return true
+	}
+	add(email.TextBodyHash)
+	add(email.HtmlBodyHash)
+	add(email.HeaderTemplateHash)
+	add(email.SummaryHash)
+	if email.Delta != nil {
+		// a delta links its base, and everything linked by the rebuilt email
+		if add(email.Delta.BaseHash) {
+			base, err := c.getStoredEmail(email.Delta.BaseHash)
+			if err != nil {
+				return nil, err
 			}
+			baseHashes, err := c.linkedHashes(base, seen)
+			if err != nil {
+				return nil, err
+			}
+			hashes = append(hashes, baseHashes...)
 		}
-		email.Resent = resent
+		rebuilt, err := c.decodeDelta(email.Delta)
+		if err != nil {
+			return nil, err
+		}
+		rebuiltHashes, err := c.linkedHashes(rebuilt, seen)
+		if err != nil {
+			return nil, err
+		}
+		return append(hashes, rebuiltHashes...), nil
 	}
-	email.HtmlBody = eml.HTMLBody
-	email.TextBody = eml.TextBody
-	for i, attach := range eml.Attachments {
-		// file size 0 == no progress eports
-		resp, err := c.xclient.UploadFile(c.ctx, attach.Data, 0, nil, false)
+	if add(email.CoreHash) {
+		core, err := c.getStoredEmail(email.CoreHash)
 		if err != nil {
 			return nil, err
 		}
-		email.Attachments[i] = pb.Attachment{
-			FileName:    attach.Filename,
-			ContentType: attach.ContentType,
-			DataHash:    resp.GetHash(),
+		coreHashes, err := c.linkedHashes(core, seen)
+		if err != nil {
+			return nil, err
 		}
+		hashes = append(hashes, coreHashes...)
 	}
-	for i, embed := range eml.EmbeddedFiles {
-		resp, err := c.xclient.UploadFile(c.ctx, embed.Data, 0, nil, false)
+	for _, hash := range []string{email.TextSegmentsHash, email.HtmlSegmentsHash} {
+		segmentHashes, err := c.segmentHashes(hash, seen)
 		if err != nil {
 			return nil, err
 		}
-		email.EmbeddedFiles[i] = pb.EmbeddedFile{
-			ContentId:   embed.CID,
-			ContentType: embed.ContentType,
-			DataHash:    resp.GetHash(),
+		hashes = append(hashes, segmentHashes...)
+	}
+	for _, embed := range email.EmbeddedFiles {
+		add(embed.DataHash)
+	}
+	for _, attach := range email.Attachments {
+		add(attach.DataHash)
+		if !add(attach.EmailHash) {
+			continue
+		}
+		inner, err := c.getStoredEmail(attach.EmailHash)
+		if err != nil {
+			return nil, err
+		}
+		innerHashes, err := c.linkedHashes(inner, seen)
+		if err != nil {
+			return nil, err
 		}
+		hashes = append(hashes, innerHashes...)
 	}
-	return email, nil
+	return hashes, nil
 }
 
 // CalculateEmailSize calculates the size of all emais
@@ -262,24 +389,20 @@ func (c *Converter) CalculateEmailSize(printProgress bool, hashes ...string) (in
 		)
 	}
 	var fileHashes = make(map[string]bool)
+	for _, hash := range hashes {
+		fileHashes[hash] = true
+	}
 	var newHashes []string
 	for _, hash := range hashes {
-		em, err := c.GetEmail(hash)
+		em, err := c.getStoredEmail(hash)
 		if err != nil {
 			return 0, err
 		}
-		for _, embed := range em.EmbeddedFiles {
-			if !fileHashes[embed.DataHash] {
-				fileHashes[embed.DataHash] = true
-				newHashes = append(newHashes, embed.DataHash)
-			}
-		}
-		for _, attach := range em.Attachments {
-			if !fileHashes[attach.DataHash] {
-				fileHashes[attach.DataHash] = true
-				newHashes = append(newHashes, attach.DataHash)
-			}
+		linked, err := c.linkedHashes(em, fileHashes)
+		if err != nil {
+			return 0, err
 		}
+		newHashes = append(newHashes, linked...)
 	}
 	hashes = append(hashes, newHashes...)
 	var size int64
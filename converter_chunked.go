@@ -6,16 +6,29 @@ import (
 
 	xpb "github.com/RTradeLtd/TxPB/v3/go"
 	"github.com/RTradeLtd/ipld-eml/pb"
+	"github.com/gogo/protobuf/proto"
 )
 
 // contains converter function to deal with chunked messages
 
 // GetEmailChunked is used to return an email from its chunked storage format
 func (c *Converter) GetEmailChunked(hash string) (*pb.Email, error) {
+	data, err := c.getChunkedData(hash)
+	if err != nil {
+		return nil, err
+	}
+	return c.decodeEmail(data)
+}
+
+// getChunkedData returns the serialized email object from a chunked email
+func (c *Converter) getChunkedData(hash string) ([]byte, error) {
 	ep, err := c.GetChunkedEmail(hash)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkVersion(ep.Version); err != nil {
+		return nil, err
+	}
 	var (
 		data []byte
 		max  = len(ep.Parts)
@@ -30,17 +43,13 @@ func (c *Converter) GetEmailChunked(hash string) (*pb.Email, error) {
 		}
 		data = append(data, resp.GetRawData()...)
 	}
-	email := new(pb.Email)
-	if err := email.Unmarshal(data); err != nil {
-		return nil, err
-	}
-	return email, nil
+	return data, nil
 }
 
 // PutEmailChunked allows storing an email as a custom ipld dag object
 // as opposed to a unixfs object type
 func (c *Converter) PutEmailChunked(email *pb.Email) (string, error) {
-	data, err := email.Marshal()
+	data, err := c.encodeEmail(email)
 	if err != nil {
 		return "", err
 	}
@@ -71,7 +80,8 @@ func (c *Converter) PutEmailChunked(email *pb.Email) (string, error) {
 		parts[int32(i)] = resp.GetHashes()[0]
 	}
 	ep := &pb.ChunkedEmail{
-		Parts: parts,
+		Parts:   parts,
+		Version: compatibleVersion,
 	}
 	epd, err := ep.Marshal()
 	if err != nil {
@@ -115,22 +125,19 @@ func (c *Converter) CalculateChunkedEmailSize(hashes ...string) (int64, error) {
 				newHashes = append(newHashes, chash)
 			}
 		}
-		em, err := c.GetEmailChunked(hash)
+		data, err := c.getChunkedData(hash)
 		if err != nil {
 			return 0, err
 		}
-		for _, embed := range em.EmbeddedFiles {
-			if !fileHashes[embed.DataHash] {
-				fileHashes[embed.DataHash] = true
-				newHashes = append(newHashes, embed.DataHash)
-			}
+		em := new(pb.Email)
+		if err := em.Unmarshal(data); err != nil {
+			return 0, err
 		}
-		for _, attach := range em.Attachments {
-			if !fileHashes[attach.DataHash] {
-				fileHashes[attach.DataHash] = true
-				newHashes = append(newHashes, attach.DataHash)
-			}
+		linked, err := c.linkedHashes(em, fileHashes)
+		if err != nil {
+			return 0, err
 		}
+		newHashes = append(newHashes, linked...)
 	}
 	var size int64
 	hashes = append(hashes, newHashes...)
@@ -146,3 +153,41 @@ func (c *Converter) CalculateChunkedEmailSize(hashes ...string) (int64, error) {
 	}
 	return size, nil
 }
+
+// ConvertToChunked copies an email stored with PutEmail into the chunked
+// format, returning the hash of the chunked email
+func (c *Converter) ConvertToChunked(hash string) (string, error) {
+	return c.convertFormat(hash, c.GetEmail, c.PutEmailChunked, c.GetEmailChunked)
+}
+
+// ConvertFromChunked copies an email stored with PutEmailChunked into the
+// unixfs format, returning the hash of the unixfs email
+func (c *Converter) ConvertFromChunked(hash string) (string, error) {
+	return c.convertFormat(hash, c.GetEmailChunked, c.PutEmail, c.GetEmail)
+}
+
+// convertFormat copies an email between formats, verifying the copy
+// decodes to the same email as the original
+func (c *Converter) convertFormat(
+	hash string,
+	get func(string) (*pb.Email, error),
+	put func(*pb.Email) (string, error),
+	getCopy func(string) (*pb.Email, error),
+) (string, error) {
+	email, err := get(hash)
+	if err != nil {
+		return "", err
+	}
+	copyHash, err := put(email)
+	if err != nil {
+		return "", err
+	}
+	copied, err := getCopy(copyHash)
+	if err != nil {
+		return "", err
+	}
+	if !proto.Equal(email, copied) {
+		return "", errors.New("converted email does not match the original " + hash)
+	}
+	return copyHash, nil
+}
@@ -3,12 +3,18 @@ package ipldeml
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/RTradeLtd/go-temporalx-sdk/client"
+	"github.com/RTradeLtd/ipld-eml/pb"
 	"github.com/gogo/protobuf/proto"
 )
 
@@ -248,3 +254,1001 @@ func TestNonChunkSizeCalc(t *testing.T) {
 	}
 	fmt.Println("size: ", size)
 }
+
+func TestConverterAttachedEmail(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverter(ctx, cl)
+	convert := func(file string) *pb.Email {
+		fh, err := os.Open(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer fh.Close()
+		email, err := converter.Convert(fh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return email
+	}
+	// sample6 has sample5 attached to it
+	email := convert("samples/sample6.eml")
+	var innerHash string
+	for _, attach := range email.Attachments {
+		if attach.EmailHash != "" {
+			if attach.DataHash != "" {
+				t.Fatal("attached email should not be stored as a file")
+			}
+			innerHash = attach.EmailHash
+		}
+	}
+	if innerHash == "" {
+		t.Fatal("attached email was not converted")
+	}
+	inner, err := converter.GetEmail(innerHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	standalone := convert("samples/sample5.eml")
+	if inner.MessageID != standalone.MessageID || inner.Subject != standalone.Subject {
+		t.Fatal("attached email does not match the standalone email")
+	}
+	if len(inner.Attachments) != len(standalone.Attachments) {
+		t.Fatal("attached email is missing attachments")
+	}
+}
+
+func TestConverterDataURIs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverter(ctx, cl)
+	image := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("not really a png"), 64))
+	html := `<p><img src="data:image/png;base64,` + image + `"><img src="data:image/png;base64,` + image + `"></p>`
+	eml := "Subject: data uris\r\nContent-Type: text/html; charset=utf-8\r\n\r\n" + html + "\n"
+	email, err := converter.Convert(strings.NewReader(eml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(email.EmbeddedFiles) != 1 || !email.EmbeddedFiles[0].DataUri {
+		t.Fatal("data uri was not lifted into an embedded file")
+	}
+	if strings.Contains(email.HtmlBody, "data:") {
+		t.Fatal("data uri left in html body")
+	}
+	if err := converter.InlineDataURIs(email); err != nil {
+		t.Fatal(err)
+	}
+	if email.HtmlBody != html || len(email.EmbeddedFiles) != 0 {
+		t.Fatal("failed to restore data uris")
+	}
+}
+
+func TestConverterSharedBodies(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverter(ctx, cl)
+	var bodyHashes []string
+	// same body delivered to two recipients
+	for _, to := range []string{"alice@example.com", "bob@example.com"} {
+		eml := "To: " + to + "\r\nSubject: shared\r\n\r\nthe same body for everyone\r\n"
+		email, err := converter.Convert(strings.NewReader(eml))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash, err := converter.PutEmail(email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored, err := converter.getStoredEmail(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.TextBody != "" || stored.TextBodyHash == "" {
+			t.Fatal("text body stored inline")
+		}
+		bodyHashes = append(bodyHashes, stored.TextBodyHash)
+		retrieved, err := converter.GetEmail(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(email, retrieved) {
+			t.Fatal("not equal")
+		}
+	}
+	if bodyHashes[0] != bodyHashes[1] {
+		t.Fatal("identical bodies are not shared")
+	}
+}
+
+func TestConverterCompactHeaders(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverterWithOpts(ctx, cl, Opts{CompactHeaders: true})
+	for _, file := range getSamples(t, "samples") {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		email, err := converter.Convert(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash, err := converter.PutEmail(email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored, err := converter.getStoredEmail(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stored.ElidedHeaders) == 0 {
+			t.Fatal("no headers were elided")
+		}
+		retrieved, err := converter.GetEmail(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(email, retrieved) {
+			t.Fatal("not equal")
+		}
+		chunkHash, err := converter.PutEmailChunked(email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		retrieved, err = converter.GetEmailChunked(chunkHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(email, retrieved) {
+			t.Fatal("not equal")
+		}
+	}
+}
+
+func TestConverterSharedHeaders(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverterWithOpts(ctx, cl, Opts{ShareHeaders: true, CompactHeaders: true})
+	var templateHashes []string
+	// same newsletter delivered to two recipients
+	for _, to := range []string{"alice@example.com", "bob@example.com"} {
+		eml := "To: " + to + "\r\nSubject: weekly\r\nMime-Version: 1.0\r\n" +
+			"X-Mailer: newsletter mailer 1.0\r\nList-Id: weekly newsletter <weekly.example.com>\r\n" +
+			"List-Unsubscribe: <https://example.com/unsubscribe>\r\n\r\nthis weeks news\r\n"
+		email, err := converter.Convert(strings.NewReader(eml))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash, err := converter.PutEmail(email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored, err := converter.getStoredEmail(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.HeaderTemplateHash == "" {
+			t.Fatal("headers stored inline")
+		}
+		templateHashes = append(templateHashes, stored.HeaderTemplateHash)
+		retrieved, err := converter.GetEmail(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(email, retrieved) {
+			t.Fatal("not equal")
+		}
+	}
+	if templateHashes[0] != templateHashes[1] {
+		t.Fatal("identical header templates are not shared")
+	}
+}
+
+func TestConverterDeterministic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverter(ctx, cl)
+	for _, file := range getSamples(t, "samples") {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var hashes, chunkHashes []string
+		for i := 0; i < 2; i++ {
+			email, err := converter.Convert(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			hash, err := converter.PutEmail(email)
+			if err != nil {
+				t.Fatal(err)
+			}
+			chunkHash, err := converter.PutEmailChunked(email)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hashes = append(hashes, hash)
+			chunkHashes = append(chunkHashes, chunkHash)
+		}
+		if hashes[0] != hashes[1] || chunkHashes[0] != chunkHashes[1] {
+			t.Fatalf("%s converted to different hashes", file)
+		}
+	}
+}
+
+func TestConverterMigrate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverter(ctx, cl)
+	// an email as stored before versions were recorded, with its body inline
+	legacy := &pb.Email{
+		Subject:  "legacy",
+		TextBody: "stored before versions were recorded",
+		Resent:   &pb.Resent{ResentMessageId: "resent@example.com"},
+	}
+	data, err := legacy.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacyHash, err := converter.putBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := converter.MigrateEmail(legacyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == legacyHash {
+		t.Fatal("legacy email was not rewritten")
+	}
+	stored, err := converter.getStoredEmail(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Version != compatibleVersion || stored.Resent != nil || stored.TextBody != "" {
+		t.Fatal("email not stored with the current schema")
+	}
+	before, err := converter.GetEmail(legacyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := converter.GetEmail(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(before, after) {
+		t.Fatal("not equal")
+	}
+	// migrating a current email gives back the same hash
+	again, err := converter.MigrateEmail(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != hash {
+		t.Fatal("current email was rewritten")
+	}
+}
+
+func TestConverterConvertFormat(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverter(ctx, cl)
+	for _, file := range getSamples(t, "samples") {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		email, err := converter.Convert(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash, err := converter.PutEmail(email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunkHash, err := converter.ConvertToChunked(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunked, err := converter.GetEmailChunked(chunkHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(email, chunked) {
+			t.Fatal("not equal")
+		}
+		// converting back gives the original object
+		unixfsHash, err := converter.ConvertFromChunked(chunkHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if unixfsHash != hash {
+			t.Fatal("converting back gave a different hash")
+		}
+	}
+}
+
+func TestConverterArchive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverter(ctx, cl)
+	hash, err := converter.PutEmail(&pb.Email{Subject: "archived"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var links []pb.ArchiveLink
+	// enough keys in the first slot to split it into a child node
+	for i, inSlot := 0, 0; len(links) < 100; i++ {
+		key := "email-" + strconv.Itoa(i)
+		if archiveIndex(key, 0) == 0 {
+			if inSlot > maxArchiveBucketSize {
+				continue
+			}
+			inSlot++
+		}
+		links = append(links, pb.ArchiveLink{Key: key, Hash: hash})
+	}
+	root, err := converter.AddToArchive("", links...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// adding the same emails in a different order, in batches, gives the same root
+	var batchRoot string
+	for i := len(links); i > 0; i -= 30 {
+		start := i - 30
+		if start < 0 {
+			start = 0
+		}
+		if batchRoot, err = converter.AddToArchive(batchRoot, links[start:i]...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if batchRoot != root {
+		t.Fatal("archive roots differ")
+	}
+	node, err := converter.getArchiveNode(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Count != uint64(len(links)) || findArchiveSlot(node, 0).ChildHash == "" {
+		t.Fatal("bad archive root", node.Count)
+	}
+	for _, link := range links {
+		found, err := converter.GetFromArchive(root, link.Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.Hash != hash {
+			t.Fatal("bad link", found)
+		}
+	}
+	if _, err := converter.GetFromArchive(root, "missing"); err != ErrNotInArchive {
+		t.Fatal("found missing key", err)
+	}
+	var walked int
+	if err := converter.WalkArchive(root, func(pb.ArchiveLink) error {
+		walked++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if walked != len(links) {
+		t.Fatal("walked", walked, "links")
+	}
+	if _, err := converter.CalculateArchiveSize(root); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConverterMailbox(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverter(ctx, cl)
+	dir, err := ioutil.TempDir("", "mailbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	data, err := ioutil.ReadFile("samples/sample1.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a maildir inbox with a sent folder
+	for _, name := range []string{
+		"cur/1585320497.M1P1.host:2,S",
+		"new/1585320400.M1P2.host",
+		"Sent/cur/1585320500.M1P3.host:2,",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, data, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	root, err := converter.ImportMailbox(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inbox, err := converter.GetFolder(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inbox.Entries) != 2 || inbox.Entries[0].SourcePath != "new/1585320400.M1P2.host" ||
+		len(inbox.Entries[1].Flags) != 1 || inbox.Entries[1].Flags[0] != `\Seen` {
+		t.Fatal("bad inbox", inbox)
+	}
+	sent, err := converter.GetFolderByPath(root, "Sent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent.Name != "Sent" || len(sent.Entries) != 1 {
+		t.Fatal("bad sent folder", sent)
+	}
+	// add a message to a new folder
+	root, err = converter.AddToFolder(root, []string{"Archive", "2020"}, sent.Entries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	if err := converter.WalkFolders(root, func(path []string, folder *pb.Folder) error {
+		paths = append(paths, strings.Join(path, "/"))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(paths, ",") != ",Archive,Archive/2020,Sent" {
+		t.Fatal("bad folders", paths)
+	}
+	if _, err := converter.GetFolderByPath(root, "Drafts"); err != ErrFolderNotFound {
+		t.Fatal("found missing folder", err)
+	}
+}
+
+func TestConverterThreads(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverter(ctx, cl)
+	var hashes []string
+	for _, file := range getSamples(t, "samples") {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		email, err := converter.Convert(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash, err := converter.PutEmail(email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, hash)
+	}
+	threadHashes, err := converter.ThreadEmails(hashes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count uint64
+	for _, hash := range threadHashes {
+		thread, err := converter.GetThread(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count += thread.Count
+	}
+	// samples 2 to 6 are one conversation
+	if len(threadHashes) != len(hashes)-4 || count != uint64(len(hashes)) {
+		t.Fatal("bad threads", len(threadHashes), count)
+	}
+}
+
+func TestConverterDuplicates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	data, err := ioutil.ReadFile("samples/sample1.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "duplicates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string][]byte{
+		"original.eml": data,
+		"copy.eml":     data,
+		"variant.eml":  append([]byte("Received: from mx2.example.com\r\n"), data...),
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), contents, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	converter := NewConverterWithOpts(ctx, cl, Opts{DuplicatePolicy: LinkVariants})
+	hashes, err := converter.AddFromDirectory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashes["copy.eml"] != hashes["original.eml"] || hashes["variant.eml"] == hashes["original.eml"] {
+		t.Fatal("bad hashes", hashes)
+	}
+	if len(converter.Duplicates()) != 2 {
+		t.Fatal("bad duplicates", converter.Duplicates())
+	}
+	// the variant only stores its trace headers, with the original as its core
+	stored, err := converter.getStoredEmail(hashes["variant.eml"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.CoreHash != hashes["original.eml"] || stored.Subject != "" {
+		t.Fatal("variant was stored whole")
+	}
+	variant, err := converter.Convert(bytes.NewReader(files["variant.eml"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := converter.GetEmail(hashes["variant.eml"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, variant) {
+		t.Fatal("retrieved variant differs from the converted variant")
+	}
+	index, err := converter.PutMessageIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a new converter loading the index skips the stored copies
+	converter = NewConverterWithOpts(ctx, cl, Opts{DuplicatePolicy: SkipDuplicates})
+	if err := converter.LoadMessageIndex(index); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := converter.AddFromDirectory(dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, duplicate := range converter.Duplicates() {
+		if duplicate.Kind != DuplicateExact || !duplicate.Skipped {
+			t.Fatal("bad duplicate", duplicate)
+		}
+	}
+}
+
+func TestConverterSplitEnvelope(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	data, err := ioutil.ReadFile("samples/sample1.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	converter := NewConverterWithOpts(ctx, cl, Opts{SplitEnvelope: true})
+	var cores []string
+	for _, recipient := range []string{"bob@example.com", "carol@example.com"} {
+		delivered := append([]byte("Delivered-To: "+recipient+"\r\n"), data...)
+		email, err := converter.Convert(bytes.NewReader(delivered))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash, err := converter.PutEmail(email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		retrieved, err := converter.GetEmail(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(email, retrieved) {
+			t.Fatal("retrieved email differs from the converted email")
+		}
+		stored, err := converter.getStoredEmail(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cores = append(cores, stored.CoreHash)
+	}
+	if cores[0] == "" || cores[0] != cores[1] {
+		t.Fatal("copies don't share a core", cores)
+	}
+}
+
+func TestConverterDeltaReplies(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	body := strings.Repeat("the plan is to ship on friday, after the last review.\r\n", 40)
+	parent := "From: alice@example.com\r\nTo: bob@example.com\r\nMessage-ID: <plan@example.com>\r\n" +
+		"Subject: plan\r\nDate: Mon, 2 Mar 2020 09:00:00 +0000\r\n\r\n" + body
+	reply := "From: bob@example.com\r\nTo: alice@example.com\r\nMessage-ID: <reply@example.com>\r\n" +
+		"In-Reply-To: <plan@example.com>\r\nReferences: <plan@example.com>\r\n" +
+		"Subject: Re: plan\r\nDate: Mon, 2 Mar 2020 10:00:00 +0000\r\n\r\nsounds good\r\n\r\n" + body
+	dir, err := ioutil.TempDir("", "delta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// files are added in name order, so the parent is added first
+	for name, contents := range map[string]string{"a-parent.eml": parent, "b-reply.eml": reply} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	converter := NewConverterWithOpts(ctx, cl, Opts{DeltaReplies: true})
+	hashes, err := converter.AddFromDirectory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := converter.getStoredEmail(hashes["b-reply.eml"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Delta == nil || stored.Delta.BaseHash != hashes["a-parent.eml"] {
+		t.Fatal("reply not stored as a delta against its parent")
+	}
+	email, err := converter.Convert(strings.NewReader(reply))
+	if err != nil {
+		t.Fatal(err)
+	}
+	retrieved, err := converter.GetEmail(hashes["b-reply.eml"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(email, retrieved) {
+		t.Fatal("retrieved reply differs from the converted reply")
+	}
+}
+
+func TestConverterSearch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverter(ctx, cl)
+	hashes, err := converter.AddFromDirectory("samples")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// index the keybase email first, then the rest into the same index
+	root, err := converter.IndexEmails("", hashes["sample1.eml"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rest []string
+	for name, hash := range hashes {
+		if name != "sample1.eml" {
+			rest = append(rest, hash)
+		}
+	}
+	if root, err = converter.IndexEmails(root, rest...); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"subject:twitter", []string{"sample1.eml"}},
+		{"from:notify@keybase.io proof", []string{"sample1.eml"}},
+		{"from:peter@golangweekly.com", []string{"sample8.eml"}},
+		{"subject:thisisateatstemail", []string{"sample2.eml", "sample3.eml", "sample4.eml", "sample5.eml", "sample6.eml"}},
+		{"subject:twitter from:peter@golangweekly.com", nil},
+		{"notaterminanyemail", nil},
+	}
+	for _, tt := range tests {
+		results, err := converter.Search(root, tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]bool, len(results))
+		for _, result := range results {
+			got[result.Hash] = true
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("Search(%q) = %v results, want %v", tt.query, len(got), len(tt.want))
+		}
+		for _, name := range tt.want {
+			if !got[hashes[name]] {
+				t.Fatalf("Search(%q) is missing %s", tt.query, name)
+			}
+		}
+	}
+}
+
+func TestConverterQueryMetadata(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverter(ctx, cl)
+	hashes, err := converter.AddFromDirectory("samples")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all []string
+	for _, hash := range hashes {
+		all = append(all, hash)
+	}
+	root, err := converter.IndexMetadata("", all...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := converter.QueryMetadata(root, MetadataQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(hashes) {
+		t.Fatal("bad number of records", len(records))
+	}
+	for i := 1; i < len(records); i++ {
+		if records[i].Date.Before(records[i-1].Date) {
+			t.Fatal("records are not ordered by date")
+		}
+	}
+	records, err = converter.QueryMetadata(root, MetadataQuery{FromDomain: "keybase.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Hash != hashes["sample1.eml"] {
+		t.Fatal("bad records for keybase.io", records)
+	}
+	records, err = converter.QueryMetadata(root, MetadataQuery{FromDomain: "rtradetechnologies.com", HasAttachments: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		if record.Attachments == 0 || record.Hash == hashes["sample1.eml"] || record.Hash == hashes["sample8.eml"] {
+			t.Fatal("bad record", record)
+		}
+	}
+	// a date range only reads the records of the emails in the range
+	dated, err := converter.QueryMetadata(root, MetadataQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, before := dated[1].Date, dated[len(dated)-1].Date
+	records, err = converter.QueryMetadata(root, MetadataQuery{After: after, Before: before})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want int
+	for _, record := range dated {
+		if !record.Date.Before(after) && record.Date.Before(before) {
+			want++
+		}
+	}
+	if len(records) != want {
+		t.Fatal("bad number of records in the date range", len(records))
+	}
+}
+
+func TestConverterDateIndex(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverter(ctx, cl)
+	hashes, err := converter.AddFromDirectory("samples")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all []string
+	for _, hash := range hashes {
+		all = append(all, hash)
+	}
+	root, err := converter.IndexDates("", all...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []pb.DateEntry
+	if err := converter.WalkDateRange(root, time.Time{}, time.Time{}, func(entry pb.DateEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(hashes) {
+		t.Fatal("bad number of entries", len(entries))
+	}
+	// only the emails sent after the first one
+	var later int
+	if err := converter.WalkDateRange(root, entries[0].Date.Add(time.Second), time.Time{}, func(entry pb.DateEntry) error {
+		later++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if later >= len(entries) {
+		t.Fatal("bad number of later entries", later)
+	}
+}
+
+func TestConverterListSummaries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := client.NewClient(client.Opts{
+		ListenAddress: listenAddress,
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	converter := NewConverter(ctx, cl)
+	var (
+		emails        []*pb.Email
+		hashes        []string
+		chunkedHashes []string
+	)
+	for _, file := range getSamples(t, "samples") {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		email, err := converter.Convert(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash, err := converter.PutEmail(email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunkedHash, err := converter.PutEmailChunked(email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		emails = append(emails, email)
+		hashes = append(hashes, hash)
+		chunkedHashes = append(chunkedHashes, chunkedHash)
+	}
+	summaries, err := converter.ListSummaries(hashes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunkedSummaries, err := converter.ListSummariesChunked(chunkedHashes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, email := range emails {
+		want := emailSummary(email)
+		want.Version = compatibleVersion
+		if !proto.Equal(&summaries[i], &want) || !proto.Equal(&chunkedSummaries[i], &want) {
+			t.Fatal("bad summary for", hashes[i])
+		}
+		// the summary link is not part of the retrieved email
+		retrieved, err := converter.GetEmail(hashes[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(email, retrieved) {
+			t.Fatal("retrieved email differs from the converted email")
+		}
+	}
+}
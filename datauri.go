@@ -0,0 +1,96 @@
+package ipldeml
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	xpb "github.com/RTradeLtd/TxPB/v3/go"
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains helpers for moving base64 data uris out of html bodies, so the
+// files they contain are stored and deduplicated like any other embedded file
+
+// data uris smaller than this are left in place, as they cost less
+// than the embedded file that would replace them
+const minDataURISize = 256
+
+var dataURIRegex = regexp.MustCompile(`data:([a-zA-Z0-9!#$&^_.+-]+/[a-zA-Z0-9!#$&^_.+-]+(?:;[a-zA-Z0-9!#$&^_.+-]+=[a-zA-Z0-9!#$&^_.+-]+)*);base64,([A-Za-z0-9+/]+=*)`)
+
+// dataURIContentID returns the content id used to reference a lifted data uri,
+// derived from its contents so the same file always gets the same reference
+func dataURIContentID(data []byte) string {
+	digest := sha256.Sum256(data)
+	return "datauri-" + hex.EncodeToString(digest[:16]) + "@ipld-eml"
+}
+
+// extractDataURIs replaces base64 data uris in the html body with cid references,
+// storing their contents as embedded files
+func (c *Converter) extractDataURIs(email *pb.Email) error {
+	var (
+		lifted = make(map[string]bool)
+		err    error
+	)
+	email.HtmlBody = dataURIRegex.ReplaceAllStringFunc(email.HtmlBody, func(uri string) string {
+		match := dataURIRegex.FindStringSubmatch(uri)
+		data, decodeErr := base64.StdEncoding.DecodeString(match[2])
+		// only lift uris we can restore byte for byte
+		if err != nil || decodeErr != nil || len(data) < minDataURISize ||
+			base64.StdEncoding.EncodeToString(data) != match[2] {
+			return uri
+		}
+		cid := dataURIContentID(data)
+		if !lifted[cid] {
+			hash, uploadErr := c.putFile(data)
+			if uploadErr != nil {
+				err = uploadErr
+				return uri
+			}
+			md := fileMetadata(nil, data)
+			md.Inline = true
+			md.TransferEncoding = "base64"
+			email.EmbeddedFiles = append(email.EmbeddedFiles, pb.EmbeddedFile{
+				ContentId:   cid,
+				ContentType: match[1],
+				DataHash:    hash,
+				Metadata:    md,
+				DataUri:     true,
+			})
+			lifted[cid] = true
+		}
+		return "cid:" + cid
+	})
+	return err
+}
+
+// InlineDataURIs reverses the extraction of data uris done by Convert, putting
+// the files back into the html body and removing them from the embedded files
+func (c *Converter) InlineDataURIs(email *pb.Email) error {
+	var (
+		embeds   []pb.EmbeddedFile
+		replacer []string
+	)
+	for _, embed := range email.EmbeddedFiles {
+		if !embed.DataUri {
+			embeds = append(embeds, embed)
+			continue
+		}
+		resp, err := c.xclient.DownloadFile(c.ctx, &xpb.DownloadRequest{Hash: embed.DataHash}, false)
+		if err != nil {
+			return err
+		}
+		replacer = append(replacer,
+			"cid:"+embed.ContentId,
+			"data:"+embed.ContentType+";base64,"+base64.StdEncoding.EncodeToString(resp.Bytes()),
+		)
+	}
+	if len(replacer) == 0 {
+		return nil
+	}
+	email.HtmlBody = strings.NewReplacer(replacer...).Replace(email.HtmlBody)
+	email.EmbeddedFiles = embeds
+	return nil
+}
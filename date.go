@@ -0,0 +1,43 @@
+package ipldeml
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains helpers for keeping track of the timezone a message was sent from
+
+// parseDate parses a date header, returning the time in utc along with the
+// original offset in seconds. invalid is set when a non-empty value fails to parse
+func parseDate(value string) (date time.Time, offset int32, invalid bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, 0, false
+	}
+	t, err := mail.ParseDate(value)
+	if err != nil {
+		return time.Time{}, 0, true
+	}
+	_, off := t.Zone()
+	return t.UTC(), int32(off), false
+}
+
+// OriginalDate returns the date of the email in the timezone it was sent from
+func OriginalDate(email *pb.Email) time.Time {
+	return inOffset(email.Date, email.DateOffset)
+}
+
+// OriginalResentDate returns the resent date in the timezone it was resent from
+func OriginalResentDate(resent *pb.Resent) time.Time {
+	return inOffset(resent.ResentDate, resent.ResentDateOffset)
+}
+
+func inOffset(t time.Time, offset int32) time.Time {
+	if offset == 0 {
+		return t.UTC()
+	}
+	return t.In(time.FixedZone("", int(offset)))
+}
@@ -0,0 +1,64 @@
+package ipldeml
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		wantUTC     string
+		wantOffset  int32
+		wantInvalid bool
+	}{
+		{"negative offset", "Fri, 27 Mar 2020 01:12:31 -0700", "2020-03-27T08:12:31Z", -7 * 3600, false},
+		{"trailing comment", "Fri, 27 Mar 2020 14:48:17 +0000 (UTC)", "2020-03-27T14:48:17Z", 0, false},
+		{"half hour offset", "Sun, 15 Mar 2020 13:41:34 +0530", "2020-03-15T08:11:34Z", 5*3600 + 1800, false},
+		{"missing", "", "0001-01-01T00:00:00Z", 0, false},
+		{"invalid", "Fri, Mar 27, 2020 at 1:12 AM", "0001-01-01T00:00:00Z", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, offset, invalid := parseDate(tt.value)
+			if got := date.Format(time.RFC3339); got != tt.wantUTC {
+				t.Errorf("date = %s, want %s", got, tt.wantUTC)
+			}
+			if offset != tt.wantOffset {
+				t.Errorf("offset = %d, want %d", offset, tt.wantOffset)
+			}
+			if invalid != tt.wantInvalid {
+				t.Errorf("invalid = %v, want %v", invalid, tt.wantInvalid)
+			}
+		})
+	}
+}
+
+func TestOriginalDate(t *testing.T) {
+	converter := NewConverter(context.Background(), nil)
+	data, err := ioutil.ReadFile("samples/sample8.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = bytes.Replace(data, []byte("Date: Fri, 27 Mar 2020 14:48:17 +0000 (UTC)"), []byte("Date: Fri, 27 Mar 2020 07:48:17 -0700"), 1)
+	email, err := converter.Convert(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if email.DateInvalid || email.RawDate != "Fri, 27 Mar 2020 07:48:17 -0700" {
+		t.Fatal("bad raw date", email.RawDate)
+	}
+	if got := email.Date.Format(time.RFC3339); got != "2020-03-27T14:48:17Z" {
+		t.Fatal("bad utc date", got)
+	}
+	if email.DateOffset != -7*3600 {
+		t.Fatal("bad date offset", email.DateOffset)
+	}
+	if got := OriginalDate(email).Format(time.RFC1123Z); got != "Fri, 27 Mar 2020 07:48:17 -0700" {
+		t.Fatal("bad original date", got)
+	}
+}
@@ -0,0 +1,126 @@
+package ipldeml
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains the date index, a prolly tree of TreeNode objects ordering stored
+// emails by date, so the emails sent in a time range are listed without
+// fetching every email
+
+// size of the date at the start of a date index key
+const dateKeySize = 12
+
+// ErrInvalidDateKey is returned for date index keys too short to hold a date
+var ErrInvalidDateKey = errors.New("invalid date index key")
+
+// IndexDates adds the stored emails with the given hashes to the date index
+// with the given root, and returns the new root. an empty root starts a new
+// index
+func (c *Converter) IndexDates(root string, hashes ...string) (string, error) {
+	entries := make([]pb.DateEntry, 0, len(hashes))
+	for _, hash := range hashes {
+		// the date and message id are always stored on the email itself
+		email, err := c.getStoredCore(hash)
+		if err != nil {
+			return "", err
+		}
+		entries = append(entries, pb.DateEntry{
+			Date:      email.Date.UTC(),
+			MessageID: email.MessageID,
+			Hash:      hash,
+		})
+	}
+	return c.AddToDateIndex(root, entries...)
+}
+
+// AddToDateIndex adds entries to the date index with the given root, and
+// returns the new root. an empty root starts a new index
+func (c *Converter) AddToDateIndex(root string, entries ...pb.DateEntry) (string, error) {
+	return addDateEntries(c, root, entries)
+}
+
+// WalkDateRange calls fn with every entry in the date index dated at or
+// after after, and before before, in date order. zero times leave the range
+// unbounded
+func (c *Converter) WalkDateRange(root string, after, before time.Time, fn func(entry pb.DateEntry) error) error {
+	return walkDateRange(c, root, after, before, fn)
+}
+
+// addDateEntries adds entries to the date index with the given root,
+// leaving out entries which are already present
+func addDateEntries(store treeNodeStore, root string, entries []pb.DateEntry) (string, error) {
+	var (
+		keys        = make(map[string]bool, len(entries))
+		treeEntries = make([]pb.TreeEntry, 0, len(entries))
+	)
+	for i := range entries {
+		key := dateKey(&entries[i])
+		if !keys[string(key)] {
+			keys[string(key)] = true
+			treeEntries = append(treeEntries, pb.TreeEntry{Key: key})
+		}
+	}
+	return putTreeEntries(store, root, treeEntries, nil)
+}
+
+// walkDateRange calls fn with the entries of the date index in range
+func walkDateRange(store treeNodeStore, root string, after, before time.Time, fn func(entry pb.DateEntry) error) error {
+	var start, end []byte
+	if !after.IsZero() {
+		start = dateKeyPrefix(after)
+	}
+	if !before.IsZero() {
+		end = dateKeyPrefix(before)
+	}
+	return walkTreeRange(store, root, start, end, func(treeEntry pb.TreeEntry) error {
+		entry, err := parseDateKey(treeEntry.Key)
+		if err != nil {
+			return err
+		}
+		return fn(entry)
+	})
+}
+
+// dateKey returns the key of an entry, which orders entries by date, then
+// message id, then hash
+func dateKey(entry *pb.DateEntry) []byte {
+	key := dateKeyPrefix(entry.Date)
+	key = append(key, entry.MessageID...)
+	key = append(key, 0)
+	return append(key, entry.Hash...)
+}
+
+// dateKeyPrefix returns the big endian unix seconds and nanoseconds of a
+// date, with the sign bit flipped so earlier dates sort first. seconds are
+// used rather than nanoseconds alone so dates outside the years an int64 of
+// nanoseconds holds, such as the zero time of invalid dates, still sort
+func dateKeyPrefix(date time.Time) []byte {
+	key := make([]byte, dateKeySize)
+	binary.BigEndian.PutUint64(key[:8], uint64(date.Unix())^(math.MaxInt64+1))
+	binary.BigEndian.PutUint32(key[8:], uint32(date.Nanosecond()))
+	return key
+}
+
+// parseDateKey is the reverse of dateKey
+func parseDateKey(key []byte) (pb.DateEntry, error) {
+	if len(key) < dateKeySize {
+		return pb.DateEntry{}, ErrInvalidDateKey
+	}
+	seconds := int64(binary.BigEndian.Uint64(key[:8]) ^ (math.MaxInt64 + 1))
+	entry := pb.DateEntry{Date: time.Unix(seconds, int64(binary.BigEndian.Uint32(key[8:dateKeySize]))).UTC()}
+	// hashes never hold a zero byte, while message ids might
+	rest := key[dateKeySize:]
+	i := bytes.LastIndexByte(rest, 0)
+	if i < 0 {
+		return pb.DateEntry{}, ErrInvalidDateKey
+	}
+	entry.MessageID, entry.Hash = string(rest[:i]), string(rest[i+1:])
+	return entry, nil
+}
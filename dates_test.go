@@ -0,0 +1,99 @@
+package ipldeml
+
+import (
+	"bytes"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+func testDateEntries(n int) []pb.DateEntry {
+	r := rand.New(rand.NewSource(1))
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := make([]pb.DateEntry, n)
+	for i := range entries {
+		entries[i] = pb.DateEntry{
+			// about a third of the emails share their date with another
+			Date:      start.Add(time.Duration(r.Intn(2*n)) * time.Hour),
+			MessageID: strconv.Itoa(i) + "@example.com",
+			Hash:      "hash-" + strconv.Itoa(i),
+		}
+	}
+	return entries
+}
+
+func TestWalkDateRange(t *testing.T) {
+	entries := testDateEntries(2000)
+	store := memoryTreeStore{}
+	root, err := addDateEntries(store, "", entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		after, before time.Time
+	}{
+		{"all", time.Time{}, time.Time{}},
+		{"after", start.Add(1000 * time.Hour), time.Time{}},
+		{"before", time.Time{}, start.Add(1000 * time.Hour)},
+		{"range", start.Add(500 * time.Hour), start.Add(1500 * time.Hour)},
+		{"empty", start.Add(500 * time.Hour), start.Add(500 * time.Hour)},
+		{"past the end", start.Add(10000 * time.Hour), time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want int
+			for _, entry := range entries {
+				if (tt.after.IsZero() || !entry.Date.Before(tt.after)) && (tt.before.IsZero() || entry.Date.Before(tt.before)) {
+					want++
+				}
+			}
+			var got []pb.DateEntry
+			err := walkDateRange(store, root, tt.after, tt.before, func(entry pb.DateEntry) error {
+				got = append(got, entry)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != want {
+				t.Fatalf("walked %v entries, want %v", len(got), want)
+			}
+			for i := 1; i < len(got); i++ {
+				if bytes.Compare(dateKey(&got[i-1]), dateKey(&got[i])) >= 0 {
+					t.Fatal("entries are not in order")
+				}
+			}
+		})
+	}
+}
+
+func TestDateKey(t *testing.T) {
+	entries := []pb.DateEntry{
+		{Date: time.Time{}, MessageID: "invalid@example.com", Hash: "a"},
+		{Date: time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC), MessageID: "old@example.com", Hash: "b"},
+		{Date: time.Date(2020, 3, 2, 9, 0, 0, 0, time.UTC), MessageID: "a@example.com", Hash: "d"},
+		{Date: time.Date(2020, 3, 2, 9, 0, 0, 0, time.UTC), MessageID: "a@example.com\x00x", Hash: "c"},
+		{Date: time.Date(2020, 3, 2, 9, 0, 0, 1, time.UTC), MessageID: "", Hash: "c"},
+	}
+	for i := range entries {
+		key := dateKey(&entries[i])
+		got, err := parseDateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Date.Equal(entries[i].Date) || got.MessageID != entries[i].MessageID || got.Hash != entries[i].Hash {
+			t.Fatalf("parseDateKey() = %+v, want %+v", got, entries[i])
+		}
+		if i > 0 && bytes.Compare(dateKey(&entries[i-1]), key) >= 0 {
+			t.Fatal("keys are not ordered by date, message id then hash at", i)
+		}
+	}
+	if _, err := parseDateKey([]byte("short")); err != ErrInvalidDateKey {
+		t.Fatal("expected an invalid key")
+	}
+}
@@ -0,0 +1,175 @@
+package ipldeml
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains delta encoding of replies, storing a reply whose parent was
+// already added as a binary delta against the parent, as replies usually
+// repeat most of their parent in their quoted text and references
+
+const (
+	// size of the base blocks matched by diffBytes
+	deltaBlockSize = 16
+	// replies whose parent is this many deltas deep are stored whole, so
+	// rebuilding an email never applies more deltas than this
+	maxDeltaDepth = 8
+)
+
+var (
+	// ErrDeltaBaseChanged is returned when the email a delta is against no
+	// longer serializes to the bytes the delta was computed against
+	ErrDeltaBaseChanged = errors.New("delta base has changed")
+	// ErrInvalidDelta is returned when a delta copies outside its base
+	ErrInvalidDelta = errors.New("invalid delta")
+)
+
+// encodeDelta returns the serialized delta of a reply against its parent,
+// linking the summary of the reply, or false when the parent isn't in the
+// message id index or the delta doesn't save enough to be worth rebuilding
+// the email from its parent
+func (c *Converter) encodeDelta(email *pb.Email, summaryHash string) ([]byte, bool, error) {
+	if len(email.InReplyTo) == 0 {
+		return nil, false, nil
+	}
+	entry := c.index[email.InReplyTo[0]]
+	if entry == nil {
+		return nil, false, nil
+	}
+	delta := &pb.EmailDelta{BaseHash: entry.Copies[0].Hash, Depth: 1}
+	parent, err := c.getStoredEmail(delta.BaseHash)
+	if err != nil {
+		return nil, false, err
+	}
+	if parent.Delta != nil {
+		if parent.Delta.Depth >= maxDeltaDepth {
+			return nil, false, nil
+		}
+		delta.Depth += parent.Delta.Depth
+	}
+	base, err := c.deltaBase(delta.BaseHash)
+	if err != nil {
+		return nil, false, err
+	}
+	target := *email
+	target.Version = EmailVersion
+	targetData, err := target.Marshal()
+	if err != nil {
+		return nil, false, err
+	}
+	digest := sha256.Sum256(base)
+	delta.BaseDigest = digest[:]
+	delta.Ops = diffBytes(base, targetData)
+	data, err := deltaEmail(delta, summaryHash).Marshal()
+	if err != nil {
+		return nil, false, err
+	}
+	// only worth it when the reply mostly repeats its parent
+	if len(data)*2 > len(targetData) {
+		return nil, false, nil
+	}
+	return data, true, nil
+}
+
+// deltaEmail returns the email object storing a delta, written with the
+// current schema version as older readers would return an empty email for it
+func deltaEmail(delta *pb.EmailDelta, summaryHash string) *pb.Email {
+	return &pb.Email{Version: EmailVersion, Delta: delta, SummaryHash: summaryHash}
+}
+
+// decodeDelta rebuilds the email stored as a delta
+func (c *Converter) decodeDelta(delta *pb.EmailDelta) (*pb.Email, error) {
+	base, err := c.deltaBase(delta.BaseHash)
+	if err != nil {
+		return nil, err
+	}
+	if digest := sha256.Sum256(base); !bytes.Equal(digest[:], delta.BaseDigest) {
+		return nil, ErrDeltaBaseChanged
+	}
+	data, err := patchBytes(base, delta.Ops)
+	if err != nil {
+		return nil, err
+	}
+	email := new(pb.Email)
+	if err := email.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	if err := upgradeEmail(email); err != nil {
+		return nil, err
+	}
+	normalizeEmail(email)
+	return email, nil
+}
+
+// deltaBase returns the serialization deltas against the email at hash are
+// computed against, which is the canonical serialization of the retrieved
+// email so it doesn't depend on the options the email was stored with
+func (c *Converter) deltaBase(hash string) ([]byte, error) {
+	base, err := c.GetEmail(hash)
+	if err != nil {
+		return nil, err
+	}
+	return base.Marshal()
+}
+
+// diffBytes returns the ops rebuilding target from base, copying runs of
+// at least deltaBlockSize bytes found in base and inserting everything else
+func diffBytes(base, target []byte) []pb.DeltaOp {
+	blocks := make(map[string]int, len(base)/deltaBlockSize)
+	for i := 0; i+deltaBlockSize <= len(base); i += deltaBlockSize {
+		if _, ok := blocks[string(base[i:i+deltaBlockSize])]; !ok {
+			blocks[string(base[i:i+deltaBlockSize])] = i
+		}
+	}
+	var (
+		ops     []pb.DeltaOp
+		literal int
+	)
+	for i := 0; i+deltaBlockSize <= len(target); {
+		offset, ok := blocks[string(target[i:i+deltaBlockSize])]
+		if !ok {
+			i++
+			continue
+		}
+		// extend the match back into the pending literal, then forward
+		start, end := i, i+deltaBlockSize
+		for start > literal && offset > 0 && base[offset-1] == target[start-1] {
+			start--
+			offset--
+		}
+		length := end - start
+		for end < len(target) && offset+length < len(base) && base[offset+length] == target[end] {
+			end++
+			length++
+		}
+		if start > literal {
+			ops = append(ops, pb.DeltaOp{Data: target[literal:start]})
+		}
+		ops = append(ops, pb.DeltaOp{Offset: uint64(offset), Length: uint64(length)})
+		i, literal = end, end
+	}
+	if literal < len(target) {
+		ops = append(ops, pb.DeltaOp{Data: target[literal:]})
+	}
+	return ops
+}
+
+// patchBytes is the reverse of diffBytes
+func patchBytes(base []byte, ops []pb.DeltaOp) ([]byte, error) {
+	var out []byte
+	for _, op := range ops {
+		if len(op.Data) > 0 {
+			out = append(out, op.Data...)
+			continue
+		}
+		if op.Offset > uint64(len(base)) || op.Length > uint64(len(base))-op.Offset {
+			return nil, ErrInvalidDelta
+		}
+		out = append(out, base[op.Offset:op.Offset+op.Length]...)
+	}
+	return out, nil
+}
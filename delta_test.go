@@ -0,0 +1,63 @@
+package ipldeml
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+func TestDiffBytes(t *testing.T) {
+	random := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(random)
+	body := strings.Repeat("the plan is to ship on friday, ", 20)
+	tests := []struct {
+		name   string
+		base   string
+		target string
+		// most bytes the ops may insert
+		maxInserted int
+	}{
+		{"empty", "", "", 0},
+		{"empty base", "", body, len(body)},
+		{"empty target", body, "", 0},
+		{"identical", body, body, 0},
+		{"appended", body, body + "sounds good", len("sounds good")},
+		{"prepended", body, "sounds good\n" + body, len("sounds good\n")},
+		{"quoted reply", body, "sounds good\n\n> " + body[:300] + "\n> " + body[300:], 32},
+		{"unrelated", body, string(random), len(random)},
+		{"shifted random", string(random), string(random[7:]) + string(random[:7]), 2 * deltaBlockSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := diffBytes([]byte(tt.base), []byte(tt.target))
+			var inserted int
+			for _, op := range ops {
+				inserted += len(op.Data)
+			}
+			if inserted > tt.maxInserted {
+				t.Errorf("inserted %v bytes, want at most %v", inserted, tt.maxInserted)
+			}
+			patched, err := patchBytes([]byte(tt.base), ops)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(patched) != tt.target {
+				t.Fatal("patched bytes differ from the target")
+			}
+		})
+	}
+}
+
+func TestPatchBytesInvalid(t *testing.T) {
+	for _, op := range []pb.DeltaOp{
+		{Offset: 4, Length: 1},
+		{Offset: 1, Length: 4},
+		{Offset: 1, Length: ^uint64(0)},
+	} {
+		if _, err := patchBytes([]byte("base"), []pb.DeltaOp{op}); err != ErrInvalidDelta {
+			t.Errorf("patchBytes(%+v) error = %v, want %v", op, err, ErrInvalidDelta)
+		}
+	}
+}
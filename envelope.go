@@ -0,0 +1,64 @@
+package ipldeml
+
+import (
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains delivery envelopes, which split the copies of a message delivered
+// to many recipients into a core shared by every copy, and an envelope per
+// copy holding the trace headers added as the copy was delivered
+
+// splitEnvelope moves the trace headers and resent blocks of an email into
+// a new envelope, leaving the core shared by every delivered copy
+func splitEnvelope(email *pb.Email) *pb.Email {
+	// older readers would return an empty email for the envelope
+	envelope := &pb.Email{Version: EmailVersion}
+	// copy so the callers headers are left as is
+	core := make(map[string]pb.Headers, len(email.Headers.Values))
+	for name, values := range email.Headers.Values {
+		if !isTraceHeader(name) {
+			core[name] = values
+			continue
+		}
+		if envelope.Headers.Values == nil {
+			envelope.Headers.Values = make(map[string]pb.Headers)
+		}
+		envelope.Headers.Values[name] = values
+	}
+	email.Headers.Values = core
+	envelope.ResentBlocks, email.ResentBlocks = email.ResentBlocks, nil
+	return envelope
+}
+
+// joinEnvelope returns the email of a delivery envelope, restoring its core
+func (c *Converter) joinEnvelope(envelope *pb.Email) (*pb.Email, error) {
+	data, err := c.getBytes(envelope.CoreHash)
+	if err != nil {
+		return nil, err
+	}
+	email, err := c.decodeEmail(data)
+	if err != nil {
+		return nil, err
+	}
+	mergeEnvelope(email, envelope)
+	normalizeEmail(email)
+	return email, nil
+}
+
+// mergeEnvelope is the reverse of splitEnvelope. the core may be a whole
+// copy of the message, as variants are stored as envelopes of the first copy,
+// so its own trace headers are replaced
+func mergeEnvelope(email, envelope *pb.Email) {
+	for name := range email.Headers.Values {
+		if isTraceHeader(name) {
+			delete(email.Headers.Values, name)
+		}
+	}
+	if len(envelope.Headers.Values) > 0 && email.Headers.Values == nil {
+		email.Headers.Values = make(map[string]pb.Headers, len(envelope.Headers.Values))
+	}
+	for name, values := range envelope.Headers.Values {
+		email.Headers.Values[name] = values
+	}
+	email.ResentBlocks = envelope.ResentBlocks
+}
@@ -0,0 +1,75 @@
+package ipldeml
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestSplitEnvelope(t *testing.T) {
+	converter := NewConverter(context.Background(), nil)
+	message := "Received: from mx.example.com by %s\r\nDelivered-To: %s\r\n" +
+		"Resent-From: list@example.com\r\nResent-To: %s\r\nResent-Date: Mon, 2 Mar 2020 10:00:00 +0000\r\n" +
+		"From: alice@example.com\r\nTo: team@example.com\r\nMessage-ID: <plan@example.com>\r\n" +
+		"Subject: plan\r\nDate: Mon, 2 Mar 2020 09:00:00 +0000\r\n\r\nthe plan\r\n"
+	convert := func(recipient string) (core, envelope []byte) {
+		email, err := converter.Convert(strings.NewReader(strings.ReplaceAll(message, "%s", recipient)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		split := *email
+		env := splitEnvelope(&split)
+		if len(email.Headers.Values) == len(split.Headers.Values) || email.ResentBlocks == nil {
+			t.Fatal("email was modified")
+		}
+		for name := range split.Headers.Values {
+			if isTraceHeader(name) {
+				t.Fatal("trace header left in core", name)
+			}
+		}
+		if core, err = split.Marshal(); err != nil {
+			t.Fatal(err)
+		}
+		if envelope, err = env.Marshal(); err != nil {
+			t.Fatal(err)
+		}
+		mergeEnvelope(&split, env)
+		canonicalizeEmail(&split)
+		if !proto.Equal(&split, email) {
+			t.Fatal("merged email differs from the original")
+		}
+		return core, envelope
+	}
+	bobCore, bobEnvelope := convert("bob@example.com")
+	carolCore, carolEnvelope := convert("carol@example.com")
+	if string(bobCore) != string(carolCore) {
+		t.Fatal("copies have different cores")
+	}
+	if string(bobEnvelope) == string(carolEnvelope) {
+		t.Fatal("copies have the same envelope")
+	}
+}
+
+func TestMergeEnvelopeVariant(t *testing.T) {
+	converter := NewConverter(context.Background(), nil)
+	message := "From: alice@example.com\r\nTo: team@example.com\r\nMessage-ID: <plan@example.com>\r\n" +
+		"Subject: plan\r\nDate: Mon, 2 Mar 2020 09:00:00 +0000\r\n\r\nthe plan\r\n"
+	original, err := converter.Convert(strings.NewReader("X-Original-To: bob@example.com\r\nReceived: from mx1.example.com\r\n" + message))
+	if err != nil {
+		t.Fatal(err)
+	}
+	variant, err := converter.Convert(strings.NewReader("Received: from mx2.example.com\r\n" + message))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// variants are stored as an envelope with the whole original as its core
+	split := *variant
+	envelope := splitEnvelope(&split)
+	mergeEnvelope(original, envelope)
+	canonicalizeEmail(original)
+	if !proto.Equal(original, variant) {
+		t.Fatal("merged email differs from the variant")
+	}
+}
@@ -13,4 +13,6 @@ require (
 	github.com/schollz/progressbar v1.0.0
 	github.com/schollz/progressbar/v2 v2.15.0
 	github.com/urfave/cli/v2 v2.2.0
+	golang.org/x/net v0.0.0-20200301022130-244492dfa37a
+	golang.org/x/text v0.3.2
 )
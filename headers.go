@@ -0,0 +1,94 @@
+package ipldeml
+
+import (
+	"bufio"
+	"bytes"
+	"net/textproto"
+	"strings"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains helpers for working with header fields in the order they appear,
+// which net/mail discards
+
+// headerField is a single unfolded header field
+type headerField struct {
+	// canonical header key
+	name  string
+	value string
+}
+
+// readHeaderFields returns the header fields of a raw message in order
+func readHeaderFields(data []byte) []headerField {
+	var (
+		fields  []headerField
+		scanner = bufio.NewScanner(bytes.NewReader(data))
+	)
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		// continuation of a folded field
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(fields) > 0 {
+				last := &fields[len(fields)-1]
+				last.value = strings.TrimSpace(last.value + " " + strings.TrimSpace(line))
+			}
+			continue
+		}
+		i := strings.Index(line, ":")
+		if i <= 0 {
+			continue
+		}
+		fields = append(fields, headerField{
+			name:  textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(line[:i])),
+			value: strings.TrimSpace(line[i+1:]),
+		})
+	}
+	return fields
+}
+
+// resentBlocks groups the resent fields of a message into blocks. a block is
+// a run of Resent-* fields, ending at any other field or a repeated field
+func resentBlocks(fields []headerField) []pb.Resent {
+	var (
+		blocks []pb.Resent
+		block  map[string]string
+	)
+	flush := func() {
+		if block != nil {
+			blocks = append(blocks, newResent(block))
+			block = nil
+		}
+	}
+	for _, field := range fields {
+		if !strings.HasPrefix(field.name, "Resent-") {
+			flush()
+			continue
+		}
+		if _, ok := block[field.name]; ok {
+			flush()
+		}
+		if block == nil {
+			block = make(map[string]string)
+		}
+		block[field.name] = field.value
+	}
+	flush()
+	return blocks
+}
+
+func newResent(block map[string]string) pb.Resent {
+	resent := pb.Resent{
+		Addresses: parseAddresses(func(key string) string {
+			return block[key]
+		}, "Resent-"),
+		ResentMessageId: strings.Trim(block["Resent-Message-Id"], "<> "),
+		RawResentDate:   block["Resent-Date"],
+	}
+	resent.ResentDate, resent.ResentDateOffset, resent.ResentDateInvalid = parseDate(resent.RawResentDate)
+	return resent
+}
@@ -0,0 +1,49 @@
+package ipldeml
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestResentBlocks(t *testing.T) {
+	eml := strings.Join([]string{
+		"Received: from c.example.com",
+		"Resent-From: carol@example.com",
+		"Resent-To: dave@example.com",
+		"Resent-Date: Sat, 28 Mar 2020 10:00:00 +0100",
+		"Resent-Message-ID: <3@example.com>",
+		"Received: from b.example.com",
+		"Resent-From: bob@example.com",
+		"Resent-To: carol@example.com,",
+		"  erin@example.com",
+		"Resent-Date: Fri, 27 Mar 2020 10:00:00 -0700",
+		"Resent-From: alice@example.com",
+		"Resent-To: bob@example.com",
+		"From: alice@example.com",
+		"Subject: resent",
+		"",
+		"hello",
+	}, "\r\n")
+	email, err := NewConverter(context.Background(), nil).Convert(strings.NewReader(eml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(email.ResentBlocks) != 3 {
+		t.Fatal("bad number of resent blocks", len(email.ResentBlocks))
+	}
+	first := email.ResentBlocks[0]
+	if first.Addresses.From[0].Address != "carol@example.com" || first.ResentMessageId != "3@example.com" {
+		t.Fatal("bad first block")
+	}
+	if first.ResentDateOffset != 3600 {
+		t.Fatal("bad first block offset")
+	}
+	second := email.ResentBlocks[1]
+	if len(second.Addresses.To) != 2 || second.Addresses.To[1].Address != "erin@example.com" {
+		t.Fatal("bad folded resent-to")
+	}
+	if email.ResentBlocks[2].Addresses.From[0].Address != "alice@example.com" {
+		t.Fatal("bad last block")
+	}
+}
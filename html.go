@@ -0,0 +1,129 @@
+package ipldeml
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// contains helpers for chunking html bodies at block element boundaries, so
+// the template sections shared by newsletters are stored once
+
+const (
+	// blocks larger than this are split into their child blocks
+	maxHTMLBlockSize = 4096
+	// blocks smaller than this are left inline
+	minHTMLBlockSize = 256
+)
+
+// blockElements are the elements html bodies are split at
+var blockElements = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true,
+	atom.Body: true, atom.Center: true, atom.Div: true, atom.Dl: true,
+	atom.Fieldset: true, atom.Footer: true, atom.Form: true, atom.H1: true,
+	atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true,
+	atom.H6: true, atom.Head: true, atom.Header: true, atom.Html: true,
+	atom.Main: true, atom.Nav: true, atom.Ol: true, atom.P: true,
+	atom.Pre: true, atom.Section: true, atom.Style: true, atom.Table: true,
+	atom.Tbody: true, atom.Td: true, atom.Tfoot: true, atom.Th: true,
+	atom.Thead: true, atom.Tr: true, atom.Ul: true, atom.Li: true,
+}
+
+// htmlBlock is the span of a block element in the raw html
+type htmlBlock struct {
+	name       atom.Atom
+	start, end int
+	children   []*htmlBlock
+}
+
+// SplitHTMLBlocks splits html into chunks at block element boundaries. blocks
+// larger than the maximum chunk size are split into their child blocks, with
+// the markup between children forming chunks of their own. joining the chunks
+// gives back the original html
+func SplitHTMLBlocks(body string) []string {
+	root := parseHTMLBlocks(body)
+	var chunks []string
+	var split func(block *htmlBlock)
+	split = func(block *htmlBlock) {
+		if block.end-block.start <= maxHTMLBlockSize || len(block.children) == 0 {
+			chunks = append(chunks, body[block.start:block.end])
+			return
+		}
+		pos := block.start
+		for _, child := range block.children {
+			if child.start > pos {
+				chunks = append(chunks, body[pos:child.start])
+			}
+			split(child)
+			pos = child.end
+		}
+		if block.end > pos {
+			chunks = append(chunks, body[pos:block.end])
+		}
+	}
+	split(root)
+	return chunks
+}
+
+// IsStoredHTMLBlock reports whether a chunk returned by SplitHTMLBlocks is
+// stored as an object of its own, shared between emails, rather than left
+// inline in the segments of the email
+func IsStoredHTMLBlock(block string) bool {
+	return len(block) >= minHTMLBlockSize
+}
+
+// parseHTMLBlocks returns a tree of the block elements in body, rooted at a
+// block spanning the whole body
+func parseHTMLBlocks(body string) *htmlBlock {
+	var (
+		root   = &htmlBlock{end: len(body)}
+		stack  = []*htmlBlock{root}
+		offset int
+		z      = html.NewTokenizer(strings.NewReader(body))
+	)
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if z.Err() != io.EOF {
+				// leave anything we couldn't tokenize as part of the root
+				return &htmlBlock{end: len(body)}
+			}
+			break
+		}
+		raw := len(z.Raw())
+		name, _ := z.TagName()
+		tag := atom.Lookup(name)
+		switch {
+		case tt == html.StartTagToken && blockElements[tag]:
+			stack = append(stack, &htmlBlock{name: tag, start: offset})
+		case tt == html.EndTagToken && blockElements[tag]:
+			// close everything up to the matching element, tolerating
+			// elements which were never closed
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].name != tag {
+					continue
+				}
+				for j := len(stack) - 1; j >= i; j-- {
+					block := stack[j]
+					block.end = offset
+					if j == i {
+						block.end = offset + raw
+					}
+					parent := stack[j-1]
+					parent.children = append(parent.children, block)
+				}
+				stack = stack[:i]
+				break
+			}
+		}
+		offset += raw
+	}
+	// close anything left open at the end of the body
+	for j := len(stack) - 1; j > 0; j-- {
+		stack[j].end = len(body)
+		stack[j-1].children = append(stack[j-1].children, stack[j])
+	}
+	return root
+}
@@ -0,0 +1,53 @@
+package ipldeml
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestSplitHTMLBlocks(t *testing.T) {
+	article := "<p>" + strings.Repeat("article text ", 400) + "</p>"
+	header := `<table class="header"><tr><td>` + strings.Repeat("logo ", 100) + `</td></tr></table>`
+	footer := `<div class="footer"><p>unsubscribe</p><p>` + strings.Repeat("legal ", 100) + `</p></div>`
+	body := "<html><body>" + header + article + footer + "<p>unclosed</body></html>"
+	chunks := SplitHTMLBlocks(body)
+	if strings.Join(chunks, "") != body {
+		t.Fatal("chunks do not join back into the body")
+	}
+	var foundHeader, foundFooter bool
+	for _, chunk := range chunks {
+		foundHeader = foundHeader || chunk == header
+		foundFooter = foundFooter || chunk == footer
+	}
+	if !foundHeader || !foundFooter {
+		t.Fatal("template sections were not split into chunks of their own", chunks)
+	}
+	// the sample newsletter should be chunked losslessly
+	data, err := ioutil.ReadFile("samples/sample8.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts, err := readParts(msg.Header, msg.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range parts {
+		if part.kind != partHTML {
+			continue
+		}
+		chunks := SplitHTMLBlocks(string(part.data))
+		if strings.Join(chunks, "") != string(part.data) {
+			t.Fatal("newsletter chunks do not join back into the body")
+		}
+		if len(chunks) < 2 {
+			t.Fatal("newsletter was not chunked")
+		}
+	}
+}
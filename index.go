@@ -0,0 +1,245 @@
+package ipldeml
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/mail"
+	"sort"
+	"strings"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains the message id index, which records the stored copies of every
+// message added with AddFromDirectory so duplicates can be detected at ingest
+
+// DuplicatePolicy decides what happens to messages whose message id is
+// already in the index
+type DuplicatePolicy int
+
+const (
+	// StoreDuplicates stores every copy of a message
+	StoreDuplicates DuplicatePolicy = iota
+	// SkipDuplicates doesn't store copies identical to a stored copy
+	SkipDuplicates
+	// LinkVariants doesn't store identical copies, and stores copies which
+	// only differ in trace headers as a delivery envelope holding their
+	// trace headers, with the first such copy as its core, recording them
+	// as variants of that copy
+	LinkVariants
+)
+
+// DuplicateKind describes how a duplicate differs from the earlier copy
+type DuplicateKind string
+
+const (
+	// DuplicateExact is a copy identical to the earlier copy
+	DuplicateExact DuplicateKind = "exact"
+	// DuplicateVariant is a copy which only differs in trace headers
+	DuplicateVariant DuplicateKind = "variant"
+	// DuplicateConflict is a different message reusing the message id
+	DuplicateConflict DuplicateKind = "conflict"
+)
+
+// Duplicate is a message found with a message id already in the index
+type Duplicate struct {
+	Kind      DuplicateKind
+	Source    string
+	MessageID string
+	// hash of the copy, which is the hash of the earlier copy when skipped
+	Hash string
+	// hash of the earlier copy
+	Original string
+	Skipped  bool
+}
+
+// traceHeaders are added to messages as they are delivered, so differ
+// between copies of a message received by different mailboxes. they include
+// the recipient headers added by mail servers, and the status headers added
+// by the mail clients and filters of the mailbox
+var traceHeaders = map[string]bool{
+	"Authentication-Results": true, "Delivered-To": true, "Envelope-To": true,
+	"Received": true, "Received-Spf": true, "Return-Path": true,
+	"Status": true, "X-Delivered-To": true, "X-Envelope-From": true,
+	"X-Envelope-To": true, "X-Gm-Message-State": true, "X-Google-Smtp-Source": true,
+	"X-Keywords": true, "X-Mozilla-Status": true, "X-Mozilla-Status2": true,
+	"X-Original-To": true, "X-Received": true, "X-Status": true,
+	"X-Uid": true, "X-Virus-Scanned": true,
+}
+
+// isTraceHeader reports if the header is added as a message is delivered
+func isTraceHeader(name string) bool {
+	return traceHeaders[name] ||
+		strings.HasPrefix(name, "Arc-") ||
+		strings.HasPrefix(name, "Resent-") ||
+		strings.HasPrefix(name, "X-Spam-")
+}
+
+// Duplicates returns the duplicates found since the converter was created
+func (c *Converter) Duplicates() []Duplicate {
+	return c.duplicates
+}
+
+// addMessage converts and stores a raw message, consulting the message
+// id index according to the duplicate policy
+func (c *Converter) addMessage(source string, data []byte) (string, error) {
+	var messageID string
+	if msg, err := mail.ReadMessage(bytes.NewReader(data)); err == nil {
+		messageID = strings.Trim(msg.Header.Get("Message-ID"), "<> ")
+	}
+	rawDigest := sha256.Sum256(data)
+	copied := pb.IndexedCopy{RawDigest: hex.EncodeToString(rawDigest[:])}
+	entry := c.index[messageID]
+	if entry != nil && c.opts.DuplicatePolicy != StoreDuplicates {
+		for _, earlier := range entry.Copies {
+			if earlier.RawDigest == copied.RawDigest {
+				c.duplicates = append(c.duplicates, Duplicate{
+					Kind:      DuplicateExact,
+					Source:    source,
+					MessageID: messageID,
+					Hash:      earlier.Hash,
+					Original:  earlier.Hash,
+					Skipped:   true,
+				})
+				return earlier.Hash, nil
+			}
+		}
+	}
+	email, err := c.Convert(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	if messageID == "" {
+		return c.PutEmail(email)
+	}
+	if copied.ContentDigest, err = contentDigest(email); err != nil {
+		return "", err
+	}
+	if entry == nil {
+		if copied.Hash, err = c.PutEmail(email); err != nil {
+			return "", err
+		}
+		c.index[messageID] = &pb.MessageIndexEntry{MessageID: messageID, Copies: []pb.IndexedCopy{copied}}
+		return copied.Hash, nil
+	}
+	duplicate := findDuplicate(entry, copied, source, messageID)
+	// the first copy with the same content is never a variant itself, so
+	// variants are always linked to a whole copy
+	if c.opts.DuplicatePolicy == LinkVariants && duplicate.Kind == DuplicateVariant {
+		copied.VariantOf = duplicate.Original
+		copied.Hash, err = c.putVariant(email, duplicate.Original)
+	} else {
+		copied.Hash, err = c.PutEmail(email)
+	}
+	if err != nil {
+		return "", err
+	}
+	duplicate.Hash = copied.Hash
+	c.duplicates = append(c.duplicates, duplicate)
+	entry.Copies = append(entry.Copies, copied)
+	return copied.Hash, nil
+}
+
+// putVariant stores an email which only differs in trace headers from the
+// email at original as a delivery envelope of its trace headers, with the
+// original as its core, returning its hash
+func (c *Converter) putVariant(email *pb.Email, original string) (string, error) {
+	stored, err := c.getStoredEmail(original)
+	if err != nil {
+		return "", err
+	}
+	variant := *email
+	envelope := splitEnvelope(&variant)
+	envelope.CoreHash = original
+	envelope.SummaryHash = stored.SummaryHash
+	data, err := envelope.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return c.putBytes(data)
+}
+
+// findDuplicate compares a copy against the earlier copies of a message
+func findDuplicate(entry *pb.MessageIndexEntry, copied pb.IndexedCopy, source, messageID string) Duplicate {
+	duplicate := Duplicate{
+		Kind:      DuplicateConflict,
+		Source:    source,
+		MessageID: messageID,
+		Hash:      copied.Hash,
+		Original:  entry.Copies[0].Hash,
+	}
+	for _, earlier := range entry.Copies {
+		switch {
+		case earlier.RawDigest == copied.RawDigest:
+			duplicate.Kind, duplicate.Original = DuplicateExact, earlier.Hash
+			return duplicate
+		case earlier.ContentDigest == copied.ContentDigest && duplicate.Kind == DuplicateConflict:
+			duplicate.Kind, duplicate.Original = DuplicateVariant, earlier.Hash
+		}
+	}
+	return duplicate
+}
+
+// contentDigest returns the hex encoded sha256 digest of the canonical
+// serialization of an email without its trace headers
+func contentDigest(email *pb.Email) (string, error) {
+	content := *email
+	content.Headers.Values = make(map[string]pb.Headers, len(email.Headers.Values))
+	for k, v := range email.Headers.Values {
+		if !isTraceHeader(k) {
+			content.Headers.Values[k] = v
+		}
+	}
+	content.ResentBlocks = nil
+	data, err := content.Marshal()
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// LookupMessageID returns the stored copies of the message with the given id
+func (c *Converter) LookupMessageID(messageID string) []pb.IndexedCopy {
+	if entry := c.index[messageID]; entry != nil {
+		return entry.Copies
+	}
+	return nil
+}
+
+// PutMessageIndex stores the message id index, returning its hash
+func (c *Converter) PutMessageIndex() (string, error) {
+	index := &pb.MessageIndex{Version: compatibleVersion}
+	for _, entry := range c.index {
+		index.Entries = append(index.Entries, *entry)
+	}
+	sort.Slice(index.Entries, func(i, j int) bool {
+		return index.Entries[i].MessageID < index.Entries[j].MessageID
+	})
+	data, err := index.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return c.putBytes(data)
+}
+
+// LoadMessageIndex replaces the message id index with the index stored at hash
+func (c *Converter) LoadMessageIndex(hash string) error {
+	data, err := c.getBytes(hash)
+	if err != nil {
+		return err
+	}
+	index := new(pb.MessageIndex)
+	if err := index.Unmarshal(data); err != nil {
+		return err
+	}
+	if err := checkVersion(index.Version); err != nil {
+		return err
+	}
+	c.index = make(map[string]*pb.MessageIndexEntry, len(index.Entries))
+	for i := range index.Entries {
+		c.index[index.Entries[i].MessageID] = &index.Entries[i]
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package ipldeml
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+func TestContentDigest(t *testing.T) {
+	converter := NewConverter(context.Background(), nil)
+	message := "Received: from %s\r\nDelivered-To: %s\r\nFrom: alice@example.com\r\n" +
+		"To: team@example.com\r\nMessage-ID: <plan@example.com>\r\nSubject: plan\r\n\r\n%s\r\n"
+	digest := func(host, to, body string) string {
+		eml := strings.Replace(strings.Replace(strings.Replace(message, "%s", host, 1), "%s", to, 1), "%s", body, 1)
+		email, err := converter.Convert(strings.NewReader(eml))
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest, err := contentDigest(email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return digest
+	}
+	original := digest("mx1.example.com", "bob@example.com", "the plan")
+	if digest("mx2.example.com", "carol@example.com", "the plan") != original {
+		t.Fatal("copies differing in trace headers have different digests")
+	}
+	if digest("mx1.example.com", "bob@example.com", "another plan") == original {
+		t.Fatal("different messages have the same digest")
+	}
+}
+
+func TestContentDigestDeliveryHeaders(t *testing.T) {
+	converter := NewConverter(context.Background(), nil)
+	message := "From: alice@example.com\r\nTo: team@example.com\r\n" +
+		"Message-ID: <plan@example.com>\r\nSubject: plan\r\n\r\nthe plan\r\n"
+	digest := func(headers string) string {
+		email, err := converter.Convert(strings.NewReader(headers + message))
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest, err := contentDigest(email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return digest
+	}
+	original := digest("")
+	for _, header := range []string{
+		"Delivered-To: bob@example.com",
+		"X-Original-To: team@example.com",
+		"Envelope-To: bob@example.com",
+		"X-Delivered-To: bob@example.com",
+		"X-Envelope-To: bob@example.com",
+		"Status: RO",
+		"X-Uid: 42",
+		"X-Spam-Status: No, score=-0.1",
+	} {
+		if digest(header+"\r\n") != original {
+			t.Errorf("copy delivered with %q has a different digest", header)
+		}
+	}
+}
+
+func TestFindDuplicate(t *testing.T) {
+	entry := &pb.MessageIndexEntry{
+		MessageID: "plan@example.com",
+		Copies: []pb.IndexedCopy{
+			{Hash: "first", RawDigest: "raw1", ContentDigest: "content1"},
+			{Hash: "second", RawDigest: "raw2", ContentDigest: "content1", VariantOf: "first"},
+		},
+	}
+	tests := []struct {
+		name         string
+		copied       pb.IndexedCopy
+		wantKind     DuplicateKind
+		wantOriginal string
+	}{
+		{"exact", pb.IndexedCopy{Hash: "second", RawDigest: "raw2", ContentDigest: "content1"}, DuplicateExact, "second"},
+		{"variant", pb.IndexedCopy{Hash: "third", RawDigest: "raw3", ContentDigest: "content1"}, DuplicateVariant, "first"},
+		{"conflict", pb.IndexedCopy{Hash: "fourth", RawDigest: "raw4", ContentDigest: "content2"}, DuplicateConflict, "first"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			duplicate := findDuplicate(entry, tt.copied, "source", entry.MessageID)
+			if duplicate.Kind != tt.wantKind || duplicate.Original != tt.wantOriginal || duplicate.Hash != tt.copied.Hash {
+				t.Fatalf("findDuplicate() = %+v, want %v of %v", duplicate, tt.wantKind, tt.wantOriginal)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package ipldeml
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains the mailbox, a tree of Folder objects holding stored emails in
+// mailbox order along with their flags, so folder trees such as imap
+// accounts can be archived as they are
+
+// ErrFolderNotFound is returned when looking up a folder which does not exist
+var ErrFolderNotFound = errors.New("folder not found")
+
+// maildirFlags maps the flags in maildir file names to imap flags
+var maildirFlags = map[rune]string{
+	'D': `\Draft`,
+	'F': `\Flagged`,
+	'P': "$Forwarded",
+	'R': `\Answered`,
+	'S': `\Seen`,
+	'T': `\Deleted`,
+}
+
+// ImportMailbox converts every email in a directory tree, storing each
+// directory as a folder, and returns the hash of the root folder. maildir
+// cur and new directories hold the messages of their parent folder, with
+// the flags and delivery time taken from the maildir file names. index and
+// control files such as dovecot-uidlist and maildirfolder are skipped
+func (c *Converter) ImportMailbox(dir string) (string, error) {
+	folder, err := c.importFolder(dir, "", "")
+	if err != nil {
+		return "", err
+	}
+	return c.PutFolder(folder)
+}
+
+// importFolder converts the emails in the directory rel under root
+func (c *Converter) importFolder(root, rel, name string) (*pb.Folder, error) {
+	messages, folders, err := readFolder(root, rel)
+	if err != nil {
+		return nil, err
+	}
+	folder := &pb.Folder{Name: name}
+	for _, m := range messages {
+		hash, err := c.addFile(filepath.Join(root, m.path))
+		if err != nil {
+			return nil, err
+		}
+		received, flags := maildirInfo(m.info)
+		folder.Entries = append(folder.Entries, pb.FolderEntry{
+			Hash:       hash,
+			Received:   received,
+			Flags:      flags,
+			SourcePath: filepath.ToSlash(m.path),
+		})
+	}
+	for _, f := range folders {
+		child, err := c.importFolder(root, filepath.Join(rel, f), f)
+		if err != nil {
+			return nil, err
+		}
+		hash, err := c.PutFolder(child)
+		if err != nil {
+			return nil, err
+		}
+		folder.Folders = append(folder.Folders, pb.FolderLink{Name: f, Hash: hash})
+	}
+	sort.SliceStable(folder.Entries, func(i, j int) bool {
+		return folder.Entries[i].Received.Before(folder.Entries[j].Received)
+	})
+	return folder, nil
+}
+
+// folderFile is a message file found by readFolder, with its path under the
+// mailbox root
+type folderFile struct {
+	path string
+	info os.FileInfo
+}
+
+// readFolder returns the message files and the names of the subfolders of
+// the directory rel under root. a directory with cur or new directories is a
+// maildir, whose messages are the files in them, so the index and control
+// files mail servers keep next to them are skipped
+func readFolder(root, rel string) ([]folderFile, []string, error) {
+	files, err := ioutil.ReadDir(filepath.Join(root, rel))
+	if err != nil {
+		return nil, nil, err
+	}
+	var maildir bool
+	for _, f := range files {
+		if f.IsDir() && (f.Name() == "cur" || f.Name() == "new") {
+			maildir = true
+		}
+	}
+	var (
+		messages []folderFile
+		folders  []string
+	)
+	for _, f := range files {
+		path := filepath.Join(rel, f.Name())
+		if !f.IsDir() {
+			if !maildir && !isMailboxControlFile(f.Name()) {
+				messages = append(messages, folderFile{path, f})
+			}
+			continue
+		}
+		switch f.Name() {
+		case "cur", "new":
+			entries, err := ioutil.ReadDir(filepath.Join(root, path))
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, m := range entries {
+				if !m.IsDir() && !isMailboxControlFile(m.Name()) {
+					messages = append(messages, folderFile{filepath.Join(path, m.Name()), m})
+				}
+			}
+		case "tmp":
+			// messages still being delivered
+		default:
+			folders = append(folders, f.Name())
+		}
+	}
+	return messages, folders, nil
+}
+
+// isMailboxControlFile reports whether a file is kept by a mail server or
+// client alongside the messages of a folder, rather than being a message
+func isMailboxControlFile(name string) bool {
+	switch name {
+	case "maildirfolder", "subscriptions", "courierimapsubscribed":
+		return true
+	}
+	return strings.HasPrefix(name, ".") ||
+		strings.HasPrefix(name, "dovecot") ||
+		strings.HasPrefix(name, "courierimap")
+}
+
+// maildirInfo returns the delivery time and flags of a message file, using
+// the maildir file name when it follows the maildir conventions
+func maildirInfo(f os.FileInfo) (time.Time, []string) {
+	received := f.ModTime().UTC()
+	if i := strings.IndexByte(f.Name(), '.'); i > 0 {
+		if secs, err := strconv.ParseInt(f.Name()[:i], 10, 64); err == nil {
+			received = time.Unix(secs, 0).UTC()
+		}
+	}
+	i := strings.LastIndex(f.Name(), ":2,")
+	if i < 0 {
+		return received, nil
+	}
+	var flags []string
+	for _, r := range f.Name()[i+3:] {
+		if flag, ok := maildirFlags[r]; ok {
+			flags = append(flags, flag)
+		}
+	}
+	return received, flags
+}
+
+// PutFolder stores a folder, returning its hash
+func (c *Converter) PutFolder(folder *pb.Folder) (string, error) {
+	stored := *folder
+	stored.Version = compatibleVersion
+	// copy so the callers folders are left in their order
+	stored.Folders = append([]pb.FolderLink(nil), folder.Folders...)
+	sort.Slice(stored.Folders, func(i, j int) bool {
+		return stored.Folders[i].Name < stored.Folders[j].Name
+	})
+	data, err := stored.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return c.putBytes(data)
+}
+
+// GetFolder returns the folder stored at hash
+func (c *Converter) GetFolder(hash string) (*pb.Folder, error) {
+	data, err := c.getBytes(hash)
+	if err != nil {
+		return nil, err
+	}
+	folder := new(pb.Folder)
+	if err := folder.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(folder.Version); err != nil {
+		return nil, err
+	}
+	for i := range folder.Entries {
+		folder.Entries[i].Received = folder.Entries[i].Received.UTC()
+	}
+	return folder, nil
+}
+
+// GetFolderByPath returns the folder at path under the root folder
+func (c *Converter) GetFolderByPath(root string, path ...string) (*pb.Folder, error) {
+	folder, err := c.GetFolder(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range path {
+		link := findFolderLink(folder, name)
+		if link == nil {
+			return nil, ErrFolderNotFound
+		}
+		if folder, err = c.GetFolder(link.Hash); err != nil {
+			return nil, err
+		}
+	}
+	return folder, nil
+}
+
+// AddToFolder appends entries to the folder at path under the root folder,
+// creating any folders in the path which do not exist, and returns the new
+// root. an empty root starts a new mailbox
+func (c *Converter) AddToFolder(root string, path []string, entries ...pb.FolderEntry) (string, error) {
+	return c.addToFolder(root, "", path, entries)
+}
+
+// addToFolder is AddToFolder for a folder with the given name
+func (c *Converter) addToFolder(hash, name string, path []string, entries []pb.FolderEntry) (string, error) {
+	folder := &pb.Folder{Name: name}
+	if hash != "" {
+		var err error
+		if folder, err = c.GetFolder(hash); err != nil {
+			return "", err
+		}
+	}
+	if len(path) == 0 {
+		folder.Entries = append(folder.Entries, entries...)
+		return c.PutFolder(folder)
+	}
+	var childHash string
+	link := findFolderLink(folder, path[0])
+	if link != nil {
+		childHash = link.Hash
+	}
+	childHash, err := c.addToFolder(childHash, path[0], path[1:], entries)
+	if err != nil {
+		return "", err
+	}
+	if link != nil {
+		link.Hash = childHash
+	} else {
+		folder.Folders = append(folder.Folders, pb.FolderLink{Name: path[0], Hash: childHash})
+	}
+	return c.PutFolder(folder)
+}
+
+// WalkFolders calls fn with every folder under the root folder, including
+// the root itself, along with its path
+func (c *Converter) WalkFolders(root string, fn func(path []string, folder *pb.Folder) error) error {
+	return c.walkFolders(root, nil, fn)
+}
+
+func (c *Converter) walkFolders(hash string, path []string, fn func(path []string, folder *pb.Folder) error) error {
+	folder, err := c.GetFolder(hash)
+	if err != nil {
+		return err
+	}
+	if err := fn(path, folder); err != nil {
+		return err
+	}
+	for _, link := range folder.Folders {
+		childPath := append(path[:len(path):len(path)], link.Name)
+		if err := c.walkFolders(link.Hash, childPath, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findFolderLink returns the link to the subfolder with the given name
+func findFolderLink(folder *pb.Folder, name string) *pb.FolderLink {
+	for i := range folder.Folders {
+		if folder.Folders[i].Name == name {
+			return &folder.Folders[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package ipldeml
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fileInfo is an os.FileInfo for a file which does not exist
+type fileInfo struct {
+	os.FileInfo
+	name    string
+	modTime time.Time
+}
+
+func (f fileInfo) Name() string       { return f.name }
+func (f fileInfo) ModTime() time.Time { return f.modTime }
+
+func TestMaildirInfo(t *testing.T) {
+	modTime := time.Date(2020, 3, 27, 14, 48, 17, 0, time.UTC)
+	tests := []struct {
+		name         string
+		wantReceived time.Time
+		wantFlags    []string
+	}{
+		{"1585320497.M1P2.host,S=1234:2,FRS", time.Unix(1585320497, 0).UTC(), []string{`\Flagged`, `\Answered`, `\Seen`}},
+		{"1585320497.M1P2.host", time.Unix(1585320497, 0).UTC(), nil},
+		{"1585320497.M1P2.host:2,", time.Unix(1585320497, 0).UTC(), nil},
+		{"sample1.eml", modTime, nil},
+		{"message:2,DT", modTime, []string{`\Draft`, `\Deleted`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received, flags := maildirInfo(fileInfo{name: tt.name, modTime: modTime})
+			if !received.Equal(tt.wantReceived) {
+				t.Fatalf("maildirInfo() received = %v, want %v", received, tt.wantReceived)
+			}
+			if !reflect.DeepEqual(flags, tt.wantFlags) {
+				t.Fatalf("maildirInfo() flags = %v, want %v", flags, tt.wantFlags)
+			}
+		})
+	}
+}
+
+func TestReadFolder(t *testing.T) {
+	root, err := ioutil.TempDir("", "maildir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	for _, path := range []string{
+		"cur/1585320497.M1P2.host:2,S",
+		"new/1585320498.M1P2.host",
+		"tmp/1585320499.M1P2.host",
+		"dovecot-uidlist",
+		"dovecot-keywords",
+		"dovecot.index",
+		"dovecot.index.log",
+		"dovecot.list.index",
+		"maildirfolder",
+		"subscriptions",
+		".Sent/cur/1585320500.M1P2.host:2,S",
+		".Sent/maildirfolder",
+		"archive/sample1.eml",
+		"archive/.DS_Store",
+	} {
+		path = filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		rel          string
+		wantMessages []string
+		wantFolders  []string
+	}{
+		{"", []string{"cur/1585320497.M1P2.host:2,S", "new/1585320498.M1P2.host"}, []string{".Sent", "archive"}},
+		{".Sent", []string{".Sent/cur/1585320500.M1P2.host:2,S"}, nil},
+		{"archive", []string{"archive/sample1.eml"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rel, func(t *testing.T) {
+			messages, folders, err := readFolder(root, filepath.FromSlash(tt.rel))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var paths []string
+			for _, m := range messages {
+				paths = append(paths, filepath.ToSlash(m.path))
+			}
+			if !reflect.DeepEqual(paths, tt.wantMessages) {
+				t.Fatalf("readFolder() messages = %v, want %v", paths, tt.wantMessages)
+			}
+			if !reflect.DeepEqual(folders, tt.wantFolders) {
+				t.Fatalf("readFolder() folders = %v, want %v", folders, tt.wantFolders)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package ipldeml
+
+import (
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains the metadata index, which records the addresses, dates, sizes,
+// attachments and headers of stored emails so structured queries such as
+// ediscovery requests are answered without fetching every email
+
+// MetadataQuery selects emails by their metadata. every field which is set
+// must match, so the zero query matches every email
+type MetadataQuery struct {
+	// FromDomain matches the sender, from and reply-to addresses. domains
+	// also match their subdomains
+	FromDomain string
+	// ToDomain matches the to addresses
+	ToDomain string
+	// CcDomain matches the cc and bcc addresses
+	CcDomain string
+	// After matches emails sent at or after the time
+	After time.Time
+	// Before matches emails sent before the time
+	Before time.Time
+	// HasAttachments matches emails with at least one attachment
+	HasAttachments bool
+	// AttachmentType matches emails with an attachment or embedded file of
+	// the content type, or of a major type such as "image/"
+	AttachmentType string
+	// MinSize and MaxSize bound the size of the bodies and files of the email
+	MinSize uint64
+	MaxSize uint64
+	// Headers must all be present
+	Headers []string
+}
+
+// IndexMetadata adds the stored emails with the given hashes to the metadata
+// index with the given root, and returns the new root. an empty root starts
+// a new index
+func (c *Converter) IndexMetadata(root string, hashes ...string) (string, error) {
+	index := new(pb.MetadataIndex)
+	if root != "" {
+		var err error
+		if index, err = c.getMetadataIndex(root); err != nil {
+			return "", err
+		}
+	}
+	var (
+		records = make(map[string][]byte, len(hashes))
+		dates   = make([]pb.DateEntry, 0, len(hashes))
+	)
+	for _, hash := range hashes {
+		email, err := c.GetEmail(hash)
+		if err != nil {
+			return "", err
+		}
+		record := emailMetadata(hash, email)
+		record.Hash = ""
+		if records[hash], err = record.Marshal(); err != nil {
+			return "", err
+		}
+		dates = append(dates, pb.DateEntry{Date: email.Date, MessageID: email.MessageID, Hash: hash})
+	}
+	entries := make([]pb.TreeEntry, 0, len(records))
+	for hash, value := range records {
+		entries = append(entries, pb.TreeEntry{Key: []byte(hash), Value: value})
+	}
+	var err error
+	if index.Records, err = putTreeEntries(c, index.Records, entries, nil); err != nil {
+		return "", err
+	}
+	if index.Dates, err = addDateEntries(c, index.Dates, dates); err != nil {
+		return "", err
+	}
+	return c.putMetadataIndex(index)
+}
+
+// QueryMetadata returns the records of the emails in the metadata index with
+// the given root matching the query, ordered by date. queries with a date
+// range only read the records of the emails in the range, found with the
+// date index kept alongside the records, while other queries read every
+// record. an empty root matches no emails
+func (c *Converter) QueryMetadata(root string, query MetadataQuery) ([]pb.MetadataRecord, error) {
+	if root == "" {
+		return nil, nil
+	}
+	index, err := c.getMetadataIndex(root)
+	if err != nil {
+		return nil, err
+	}
+	var records []pb.MetadataRecord
+	add := func(hash string, value []byte) error {
+		var record pb.MetadataRecord
+		if err := record.Unmarshal(value); err != nil {
+			return err
+		}
+		record.Hash = hash
+		record.Date = record.Date.UTC()
+		if query.matches(&record) {
+			records = append(records, record)
+		}
+		return nil
+	}
+	if query.After.IsZero() && query.Before.IsZero() {
+		err = walkTree(c, index.Records, func(entry pb.TreeEntry) error {
+			return add(string(entry.Key), entry.Value)
+		})
+	} else {
+		err = walkDateRange(c, index.Dates, query.After, query.Before, func(entry pb.DateEntry) error {
+			value, ok, err := getTreeValue(c, index.Records, []byte(entry.Hash))
+			if err != nil || !ok {
+				return err
+			}
+			return add(entry.Hash, value)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].Date.Equal(records[j].Date) {
+			return records[i].Date.Before(records[j].Date)
+		}
+		return records[i].Hash < records[j].Hash
+	})
+	return records, nil
+}
+
+// matches reports if a record matches the query
+func (q *MetadataQuery) matches(record *pb.MetadataRecord) bool {
+	switch {
+	case q.FromDomain != "" && !hasDomain(record.FromDomains, q.FromDomain),
+		q.ToDomain != "" && !hasDomain(record.ToDomains, q.ToDomain),
+		q.CcDomain != "" && !hasDomain(record.CcDomains, q.CcDomain),
+		!q.After.IsZero() && record.Date.Before(q.After),
+		!q.Before.IsZero() && !record.Date.Before(q.Before),
+		q.HasAttachments && record.Attachments == 0,
+		q.MinSize > 0 && record.MessageSize < q.MinSize,
+		q.MaxSize > 0 && record.MessageSize > q.MaxSize:
+		return false
+	}
+	if q.AttachmentType != "" {
+		want := strings.ToLower(q.AttachmentType)
+		var found bool
+		for _, contentType := range record.AttachmentTypes {
+			if contentType == want || (strings.HasSuffix(want, "/") && strings.HasPrefix(contentType, want)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, name := range q.Headers {
+		name = textproto.CanonicalMIMEHeaderKey(name)
+		i := sort.SearchStrings(record.Headers, name)
+		if i == len(record.Headers) || record.Headers[i] != name {
+			return false
+		}
+	}
+	return true
+}
+
+// hasDomain reports if any of the domains is domain, or one of its subdomains
+func hasDomain(domains []string, domain string) bool {
+	domain = strings.ToLower(strings.TrimPrefix(domain, "@"))
+	for _, d := range domains {
+		if d == domain || strings.HasSuffix(d, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// emailMetadata returns the metadata record of an email
+func emailMetadata(hash string, email *pb.Email) pb.MetadataRecord {
+	addresses := email.Addresses
+	from := append([]pb.Address(nil), addresses.From...)
+	if addresses.Sender != nil {
+		from = append(from, *addresses.Sender)
+	}
+	record := pb.MetadataRecord{
+		Hash:        hash,
+		FromDomains: addressDomains(from, addresses.ReplyTo),
+		ToDomains:   addressDomains(addresses.To),
+		CcDomains:   addressDomains(addresses.Cc, addresses.Bcc),
+		Date:        email.Date,
+		MessageSize: emailSize(email),
+		Attachments: uint32(len(email.Attachments)),
+	}
+	var contentTypes []string
+	for _, attach := range email.Attachments {
+		contentTypes = append(contentTypes, strings.ToLower(attach.ContentType))
+	}
+	for _, embed := range email.EmbeddedFiles {
+		contentTypes = append(contentTypes, strings.ToLower(embed.ContentType))
+	}
+	record.AttachmentTypes = sortedSet(contentTypes)
+	for name := range email.Headers.Values {
+		record.Headers = append(record.Headers, name)
+	}
+	sort.Strings(record.Headers)
+	return record
+}
+
+// addressDomains returns the sorted lower case domains of the addresses
+func addressDomains(lists ...[]pb.Address) []string {
+	var domains []string
+	for _, list := range lists {
+		for _, address := range list {
+			if i := strings.LastIndexByte(address.Address, '@'); i >= 0 && i < len(address.Address)-1 {
+				domains = append(domains, strings.ToLower(address.Address[i+1:]))
+			}
+		}
+	}
+	return sortedSet(domains)
+}
+
+// sortedSet returns the sorted unique non empty values
+func sortedSet(values []string) []string {
+	sort.Strings(values)
+	var set []string
+	for i, value := range values {
+		if value != "" && (i == 0 || values[i-1] != value) {
+			set = append(set, value)
+		}
+	}
+	return set
+}
+
+// getMetadataIndex returns the metadata index stored at hash
+func (c *Converter) getMetadataIndex(hash string) (*pb.MetadataIndex, error) {
+	data, err := c.getBytes(hash)
+	if err != nil {
+		return nil, err
+	}
+	index := new(pb.MetadataIndex)
+	if err := index.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(index.Version); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// putMetadataIndex stores a metadata index, returning its hash
+func (c *Converter) putMetadataIndex(index *pb.MetadataIndex) (string, error) {
+	index.Version = compatibleVersion
+	data, err := index.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return c.putBytes(data)
+}
@@ -0,0 +1,107 @@
+package ipldeml
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+func TestEmailMetadata(t *testing.T) {
+	date := time.Date(2020, 3, 2, 9, 0, 0, 0, time.UTC)
+	email := &pb.Email{
+		Headers: pb.Header{Values: map[string]pb.Headers{
+			"From": {}, "Subject": {}, "List-Unsubscribe": {},
+		}},
+		Addresses: pb.Addresses{
+			Sender:  &pb.Address{Address: "list@Lists.Example.com"},
+			From:    []pb.Address{{Address: "alice@example.com"}},
+			ReplyTo: []pb.Address{{Address: "alice@example.com"}},
+			To:      []pb.Address{{Address: "bob@example.org"}, {Address: "not an address"}},
+			Bcc:     []pb.Address{{Address: "carol@example.net"}},
+		},
+		Date:     date,
+		TextBody: "the plan",
+		Attachments: []pb.Attachment{
+			{ContentType: "application/PDF", Metadata: pb.FileMetadata{FileSize: 1000}},
+			{ContentType: "image/png", Metadata: pb.FileMetadata{FileSize: 10}},
+		},
+		EmbeddedFiles: []pb.EmbeddedFile{
+			{ContentType: "image/png", Metadata: pb.FileMetadata{FileSize: 20}},
+		},
+	}
+	want := pb.MetadataRecord{
+		Hash:            "hash",
+		FromDomains:     []string{"example.com", "lists.example.com"},
+		ToDomains:       []string{"example.org"},
+		CcDomains:       []string{"example.net"},
+		Date:            date,
+		MessageSize:     1038,
+		Attachments:     2,
+		AttachmentTypes: []string{"application/pdf", "image/png"},
+		Headers:         []string{"From", "List-Unsubscribe", "Subject"},
+	}
+	if got := emailMetadata("hash", email); !reflect.DeepEqual(got, want) {
+		t.Fatalf("emailMetadata() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMetadataQueryMatches(t *testing.T) {
+	record := &pb.MetadataRecord{
+		FromDomains:     []string{"mail.example.com"},
+		ToDomains:       []string{"example.org"},
+		CcDomains:       []string{"example.net"},
+		Date:            time.Date(2020, 3, 2, 9, 0, 0, 0, time.UTC),
+		MessageSize:     1000,
+		Attachments:     1,
+		AttachmentTypes: []string{"application/pdf", "image/png"},
+		Headers:         []string{"From", "List-Unsubscribe", "Subject"},
+	}
+	tests := []struct {
+		name  string
+		query MetadataQuery
+		want  bool
+	}{
+		{"empty", MetadataQuery{}, true},
+		{"from subdomain", MetadataQuery{FromDomain: "Example.com"}, true},
+		{"from domain", MetadataQuery{FromDomain: "mail.example.com"}, true},
+		{"from other domain", MetadataQuery{FromDomain: "ample.com"}, false},
+		{"to domain", MetadataQuery{ToDomain: "example.org"}, true},
+		{"to is not cc", MetadataQuery{ToDomain: "example.net"}, false},
+		{"cc domain", MetadataQuery{CcDomain: "example.net"}, true},
+		{"in range", MetadataQuery{
+			After:  time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+			Before: time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC),
+		}, true},
+		{"after is inclusive", MetadataQuery{After: record.Date}, true},
+		{"before is exclusive", MetadataQuery{Before: record.Date}, false},
+		{"has attachments", MetadataQuery{HasAttachments: true}, true},
+		{"attachment type", MetadataQuery{AttachmentType: "Application/PDF"}, true},
+		{"attachment major type", MetadataQuery{AttachmentType: "image/"}, true},
+		{"missing attachment type", MetadataQuery{AttachmentType: "image/jpeg"}, false},
+		{"size in range", MetadataQuery{MinSize: 1000, MaxSize: 1000}, true},
+		{"too small", MetadataQuery{MinSize: 1001}, false},
+		{"too large", MetadataQuery{MaxSize: 999}, false},
+		{"headers", MetadataQuery{Headers: []string{"list-unsubscribe", "FROM"}}, true},
+		{"missing header", MetadataQuery{Headers: []string{"From", "X-Mailer"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.matches(record); got != tt.want {
+				t.Fatalf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryMetadataEmptyRoot(t *testing.T) {
+	records, err := NewConverter(context.Background(), nil).QueryMetadata("", MetadataQuery{FromDomain: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records != nil {
+		t.Fatal("expected no records", records)
+	}
+}
@@ -0,0 +1,378 @@
+package ipldeml
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// contains helpers for walking the mime tree of a message. parsemail
+// hands us bodies without transfer or charset decoding, and drops the
+// part headers, so we walk the message ourselves to fill in the gaps
+
+type partKind int
+
+const (
+	partText partKind = iota
+	partHTML
+	partAttachment
+	partEmbedded
+)
+
+// mimePart is a leaf part of a message, classified the same way parsemail does
+type mimePart struct {
+	kind   partKind
+	header textproto.MIMEHeader
+	// transfer decoded contents of the part
+	data []byte
+	// set when the transfer encoding of a text part couldn't be undone,
+	// leaving data as it was in the message
+	undecoded bool
+}
+
+var (
+	// wordDecoder decodes RFC 2047 encoded words in any charset we know of
+	wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+	// addressParser decodes encoded display names with wordDecoder
+	addressParser = &mail.AddressParser{WordDecoder: wordDecoder}
+)
+
+// charsetReader returns a reader converting input from charset to utf-8
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, err
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+// readParts returns the leaf parts of a message in the order parsemail visits them
+func readParts(header mail.Header, body io.Reader) ([]mimePart, error) {
+	mediaType, params := "text/plain", map[string]string{}
+	if ct := header.Get("Content-Type"); ct != "" {
+		var err error
+		mediaType, params, err = mime.ParseMediaType(ct)
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch mediaType {
+	case "multipart/mixed":
+		return readMultipart(body, params["boundary"], true)
+	case "multipart/alternative", "multipart/related":
+		return readMultipart(body, params["boundary"], false)
+	case "text/plain", "text/html":
+		hdr := textproto.MIMEHeader(header)
+		data, ok, err := decodeTextTransfer(body, hdr.Get("Content-Transfer-Encoding"))
+		if err != nil {
+			return nil, err
+		}
+		kind := partText
+		if mediaType == "text/html" {
+			kind = partHTML
+		}
+		return []mimePart{{kind: kind, header: hdr, data: data, undecoded: !ok}}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func readMultipart(body io.Reader, boundary string, mixed bool) ([]mimePart, error) {
+	var parts []mimePart
+	mr := multipart.NewReader(body, boundary)
+	for {
+		part, err := mr.NextRawPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		mediaType, params, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if err != nil {
+			return nil, err
+		}
+		encoding := part.Header.Get("Content-Transfer-Encoding")
+		var kind partKind
+		switch {
+		case mediaType == "multipart/alternative" || mediaType == "multipart/related":
+			sub, err := readMultipart(part, params["boundary"], false)
+			if err != nil {
+				return nil, err
+			}
+			if mixed {
+				// parsemail keeps only the last body container of a mixed message
+				parts = onlyAttachments(parts)
+			}
+			parts = append(parts, sub...)
+			continue
+		case mixed && part.FileName() != "":
+			kind = partAttachment
+		case !mixed && mediaType == "text/plain":
+			kind = partText
+		case !mixed && mediaType == "text/html":
+			kind = partHTML
+		// parsemail uses NextPart which strips quoted-printable encoding headers
+		case !mixed && encoding != "" && !strings.EqualFold(encoding, "quoted-printable"):
+			kind = partEmbedded
+		default:
+			continue
+		}
+		if kind == partText || kind == partHTML {
+			data, ok, err := decodeTextTransfer(part, encoding)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, mimePart{kind: kind, header: part.Header, data: data, undecoded: !ok})
+			continue
+		}
+		data, err := decodeTransfer(part, encoding)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, mimePart{kind: kind, header: part.Header, data: data})
+	}
+	return parts, nil
+}
+
+func onlyAttachments(parts []mimePart) []mimePart {
+	var out []mimePart
+	for _, part := range parts {
+		if part.kind == partAttachment {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// decodeTransfer undoes the content transfer encoding of a part
+func decodeTransfer(r io.Reader, encoding string) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, r))
+	case "quoted-printable":
+		return ioutil.ReadAll(quotedprintable.NewReader(r))
+	default:
+		return ioutil.ReadAll(r)
+	}
+}
+
+// decodeTextTransfer is decodeTransfer for text and html parts, which
+// returns the undecoded bytes and false when the part isn't validly encoded,
+// as parsemail stores such bodies as they are
+func decodeTextTransfer(r io.Reader, encoding string) ([]byte, bool, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, false, err
+	}
+	data, err := decodeTransfer(bytes.NewReader(raw), encoding)
+	if err != nil {
+		return raw, false, nil
+	}
+	return data, true, nil
+}
+
+// partCharset returns the lower cased charset declared by a part
+func partCharset(header textproto.MIMEHeader) string {
+	_, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(params["charset"])
+}
+
+// decodeCharset converts data from charset to utf-8, returning false
+// if the charset is unknown or the result is not valid utf-8
+func decodeCharset(data []byte, charset string) (string, bool) {
+	switch charset {
+	case "", "utf-8", "utf8", "us-ascii":
+		return string(data), utf8.Valid(data)
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return "", false
+	}
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil || !utf8.Valid(decoded) {
+		return "", false
+	}
+	return string(decoded), true
+}
+
+// body accumulates the decoded contents of text parts
+type body struct {
+	text    strings.Builder
+	raw     bytes.Buffer
+	charset string
+	failed  bool
+}
+
+func (b *body) add(part mimePart) {
+	// match parsemail which trims the trailing newline of every part
+	data := bytes.TrimSuffix(part.data, []byte("\n"))
+	charset := partCharset(part.header)
+	if b.charset == "" {
+		b.charset = charset
+	}
+	b.raw.Write(data)
+	text, ok := decodeCharset(data, charset)
+	if !ok || part.undecoded {
+		b.failed = true
+	}
+	b.text.WriteString(text)
+}
+
+// result returns the utf-8 body, or the raw bytes if decoding failed
+func (b *body) result() (string, []byte) {
+	if b.failed {
+		return "", b.raw.Bytes()
+	}
+	return b.text.String(), nil
+}
+
+// decodeHeader decodes RFC 2047 encoded words, always returning valid utf-8
+func decodeHeader(value string) string {
+	if decoded, err := wordDecoder.DecodeHeader(value); err == nil {
+		value = decoded
+	}
+	return validUTF8(value)
+}
+
+// validUTF8 replaces any invalid utf-8 sequences so the value is safe to
+// store in a protocol buffer string field
+func validUTF8(value string) string {
+	return strings.ToValidUTF8(value, string(utf8.RuneError))
+}
+
+// partFileName returns the decoded file name of a part, handling RFC 2231
+// parameters in charsets the mime package does not support
+func partFileName(header textproto.MIMEHeader) string {
+	for _, field := range []struct{ header, param string }{
+		{"Content-Disposition", "filename"},
+		{"Content-Type", "name"},
+	} {
+		value := header.Get(field.header)
+		if value == "" {
+			continue
+		}
+		if name := decodeExtendedParam(value, field.param); name != "" {
+			return name
+		}
+		if _, params, err := mime.ParseMediaType(value); err == nil && params[field.param] != "" {
+			return decodeHeader(params[field.param])
+		}
+	}
+	return ""
+}
+
+// decodeExtendedParam decodes an RFC 2231 parameter, including continuations
+func decodeExtendedParam(value, param string) string {
+	var (
+		sections = make(map[string]string)
+		encoded  = make(map[string]bool)
+	)
+	for _, kv := range strings.Split(value, ";") {
+		i := strings.Index(kv, "=")
+		if i < 0 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(kv[:i]))
+		val := strings.Trim(strings.TrimSpace(kv[i+1:]), `"`)
+		if !strings.HasPrefix(key, param+"*") {
+			continue
+		}
+		key = strings.TrimPrefix(key, param+"*")
+		if strings.HasSuffix(key, "*") || key == "" {
+			key = strings.TrimSuffix(key, "*")
+			encoded[key] = true
+		}
+		sections[key] = val
+	}
+	var (
+		charset string
+		raw     []byte
+		keys    = []string{""}
+	)
+	if _, ok := sections[""]; !ok {
+		keys = nil
+		for i := 0; ; i++ {
+			if _, ok := sections[strconv.Itoa(i)]; !ok {
+				break
+			}
+			keys = append(keys, strconv.Itoa(i))
+		}
+	}
+	for i, key := range keys {
+		val := sections[key]
+		if encoded[key] {
+			if i == 0 {
+				// charset'language'value
+				fields := strings.SplitN(val, "'", 3)
+				if len(fields) != 3 {
+					return ""
+				}
+				charset, val = strings.ToLower(fields[0]), fields[2]
+			}
+			unescaped, err := url.PathUnescape(val)
+			if err != nil {
+				return ""
+			}
+			val = unescaped
+		}
+		raw = append(raw, val...)
+	}
+	if len(raw) == 0 {
+		return ""
+	}
+	if text, ok := decodeCharset(raw, charset); ok {
+		return text
+	}
+	return validUTF8(string(raw))
+}
+
+// fileMetadata describes the decoded contents of a file and the part containing it
+func fileMetadata(header textproto.MIMEHeader, data []byte) pb.FileMetadata {
+	digest := sha256.Sum256(data)
+	md := pb.FileMetadata{
+		FileSize:         uint64(len(data)),
+		Sha256:           hex.EncodeToString(digest[:]),
+		TransferEncoding: strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding"))),
+		Headers: pb.Header{
+			Values: make(map[string]pb.Headers, len(header)),
+		},
+	}
+	for k, v := range header {
+		values := make([]string, len(v))
+		for i, value := range v {
+			values[i] = decodeHeader(value)
+		}
+		md.Headers.Values[k] = pb.Headers{Values: values}
+	}
+	disposition, params, err := mime.ParseMediaType(header.Get("Content-Disposition"))
+	if err != nil {
+		return md
+	}
+	md.Disposition = disposition
+	md.Inline = disposition == "inline"
+	if date, _, invalid := parseDate(params["creation-date"]); !invalid && !date.IsZero() {
+		md.CreationDate = &date
+	}
+	if date, _, invalid := parseDate(params["modification-date"]); !invalid && !date.IsZero() {
+		md.ModificationDate = &date
+	}
+	return md
+}
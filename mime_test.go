@@ -0,0 +1,101 @@
+package ipldeml
+
+import (
+	"context"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConvertCharsets(t *testing.T) {
+	converter := NewConverter(context.Background(), nil)
+	type args struct {
+		eml string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantSubject string
+		wantText    string
+		wantCharset string
+		wantRaw     bool
+	}{
+		{"windows-1252", args{"Subject: =?windows-1252?Q?caf=E9?= menu\r\nContent-Type: text/plain; charset=windows-1252\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\ncaf=E9\n"}, "café menu", "café", "windows-1252", false},
+		{"iso-2022-jp", args{"Subject: =?ISO-2022-JP?B?GyRCJDMkcyRLJEEkTxsoQg==?=\r\nContent-Type: text/plain; charset=ISO-2022-JP\r\n\r\n\x1b$B$3$s$K$A$O\x1b(B\n"}, "こんにちは", "こんにちは", "iso-2022-jp", false},
+		{"invalid utf-8", args{"Subject: test\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n\xff\xfe\n"}, "test", "", "utf-8", true},
+		{"unknown charset", args{"Subject: test\r\nContent-Type: text/plain; charset=x-unknown\r\n\r\nhello\n"}, "test", "", "x-unknown", true},
+		{"bad base64", args{"Subject: test\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: base64\r\n\r\n!!not base64!!\n"}, "test", "", "utf-8", true},
+		{"no charset", args{"Subject: test\r\n\r\nhello\n"}, "test", "hello", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email, err := converter.Convert(strings.NewReader(tt.args.eml))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if email.Subject != tt.wantSubject {
+				t.Errorf("subject = %q, want %q", email.Subject, tt.wantSubject)
+			}
+			if email.TextBody != tt.wantText {
+				t.Errorf("text body = %q, want %q", email.TextBody, tt.wantText)
+			}
+			if email.TextCharset != tt.wantCharset {
+				t.Errorf("charset = %q, want %q", email.TextCharset, tt.wantCharset)
+			}
+			if (len(email.TextBodyRaw) > 0) != tt.wantRaw {
+				t.Errorf("raw body = %q, want raw %v", email.TextBodyRaw, tt.wantRaw)
+			}
+		})
+	}
+}
+
+func TestPartFileName(t *testing.T) {
+	tests := []struct {
+		name        string
+		disposition string
+		want        string
+	}{
+		{"plain", `attachment; filename="report.pdf"`, "report.pdf"},
+		{"rfc 2047", `attachment; filename="=?UTF-8?B?w6l0w6kucGRm?="`, "été.pdf"},
+		{"rfc 2231 utf-8", `attachment; filename*=UTF-8''%C3%A9t%C3%A9.pdf`, "été.pdf"},
+		{"rfc 2231 latin1", `attachment; filename*=windows-1252''%E9t%E9.pdf`, "été.pdf"},
+		{"rfc 2231 continuation", `attachment; filename*0*=windows-1252''%E9t; filename*1*=%E9.pdf`, "été.pdf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := textproto.MIMEHeader{"Content-Disposition": {tt.disposition}}
+			if got := partFileName(header); got != tt.want {
+				t.Errorf("partFileName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileMetadata(t *testing.T) {
+	header := textproto.MIMEHeader{
+		"Content-Type":              {"application/pdf; name=report.pdf"},
+		"Content-Transfer-Encoding": {"Base64"},
+		"Content-Disposition": {`inline; filename="report.pdf"; ` +
+			`creation-date="Fri, 27 Mar 2020 01:12:31 -0700"; modification-date="Sat, 28 Mar 2020 01:12:31 -0700"`},
+	}
+	md := fileMetadata(header, []byte("hello world"))
+	if md.FileSize != 11 {
+		t.Fatal("bad size")
+	}
+	if md.Sha256 != "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9" {
+		t.Fatal("bad digest")
+	}
+	if md.Disposition != "inline" || !md.Inline || md.TransferEncoding != "base64" {
+		t.Fatal("bad disposition")
+	}
+	if md.CreationDate == nil || md.CreationDate.Format(time.RFC3339) != "2020-03-27T08:12:31Z" {
+		t.Fatal("bad creation date")
+	}
+	if md.ModificationDate == nil || md.ModificationDate.Day() != 28 {
+		t.Fatal("bad modification date")
+	}
+	if md.Headers.Values["Content-Type"].Values[0] != "application/pdf; name=report.pdf" {
+		t.Fatal("part headers not captured")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
+	github_com_gogo_protobuf_sortkeys "github.com/gogo/protobuf/sortkeys"
 	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
 	io "io"
 	math "math"
@@ -30,6 +31,8 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 type ChunkedEmail struct {
 	// maps the chunk part to its hash
 	Parts map[int32]string `protobuf:"bytes,1,rep,name=parts,proto3" json:"parts,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// schema version the chunked email was stored with
+	Version uint32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
 }
 
 func (m *ChunkedEmail) Reset()         { *m = ChunkedEmail{} }
@@ -42,16 +45,12 @@ func (m *ChunkedEmail) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
 func (m *ChunkedEmail) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_ChunkedEmail.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
+	return b[:n], nil
 }
 func (m *ChunkedEmail) XXX_Merge(src proto.Message) {
 	xxx_messageInfo_ChunkedEmail.Merge(m, src)
@@ -72,6 +71,13 @@ func (m *ChunkedEmail) GetParts() map[int32]string {
 	return nil
 }
 
+func (m *ChunkedEmail) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 // Email is an ERFC5322 compatible protocol buffer intended to be used
 // as an IPLD object type, allowing long-term space-efficient archiving of data
 // taken from https://github.com/DusanKasan/parsemail/blob/master/parsemail.go
@@ -83,13 +89,58 @@ type Email struct {
 	MessageID  string    `protobuf:"bytes,5,opt,name=messageID,proto3" json:"messageID,omitempty"`
 	InReplyTo  []string  `protobuf:"bytes,6,rep,name=inReplyTo,proto3" json:"inReplyTo,omitempty"`
 	References []string  `protobuf:"bytes,7,rep,name=references,proto3" json:"references,omitempty"`
-	Resent     *Resent   `protobuf:"bytes,8,opt,name=resent,proto3" json:"resent,omitempty"`
-	HtmlBody   string    `protobuf:"bytes,10,opt,name=htmlBody,proto3" json:"htmlBody,omitempty"`
-	TextBody   string    `protobuf:"bytes,11,opt,name=textBody,proto3" json:"textBody,omitempty"`
+	// deprecated: only set by objects stored before resentBlocks existed
+	Resent   *Resent `protobuf:"bytes,8,opt,name=resent,proto3" json:"resent,omitempty"`
+	HtmlBody string  `protobuf:"bytes,10,opt,name=htmlBody,proto3" json:"htmlBody,omitempty"`
+	TextBody string  `protobuf:"bytes,11,opt,name=textBody,proto3" json:"textBody,omitempty"`
 	// a slice is nil by default
 	Attachments []Attachment `protobuf:"bytes,12,rep,name=attachments,proto3" json:"attachments"`
 	// a slice is nil by default
 	EmbeddedFiles []EmbeddedFile `protobuf:"bytes,13,rep,name=embeddedFiles,proto3" json:"embeddedFiles"`
+	// original charset of the text body, empty if none was declared
+	TextCharset string `protobuf:"bytes,14,opt,name=textCharset,proto3" json:"textCharset,omitempty"`
+	// original charset of the html body, empty if none was declared
+	HtmlCharset string `protobuf:"bytes,15,opt,name=htmlCharset,proto3" json:"htmlCharset,omitempty"`
+	// raw text body, only set when it could not be decoded to utf-8
+	TextBodyRaw []byte `protobuf:"bytes,16,opt,name=textBodyRaw,proto3" json:"textBodyRaw,omitempty"`
+	// raw html body, only set when it could not be decoded to utf-8
+	HtmlBodyRaw []byte `protobuf:"bytes,17,opt,name=htmlBodyRaw,proto3" json:"htmlBodyRaw,omitempty"`
+	// offset of the original date from utc in seconds
+	DateOffset int32 `protobuf:"varint,18,opt,name=dateOffset,proto3" json:"dateOffset,omitempty"`
+	// the unparsed date header
+	RawDate string `protobuf:"bytes,19,opt,name=rawDate,proto3" json:"rawDate,omitempty"`
+	// set when the date header is present but could not be parsed
+	DateInvalid bool `protobuf:"varint,20,opt,name=dateInvalid,proto3" json:"dateInvalid,omitempty"`
+	// every block of resent fields, in header order so the most recent is first
+	ResentBlocks []Resent `protobuf:"bytes,21,rep,name=resentBlocks,proto3" json:"resentBlocks"`
+	// hash of the unixfs object holding the text body, set instead of textBody when stored
+	TextBodyHash string `protobuf:"bytes,22,opt,name=textBodyHash,proto3" json:"textBodyHash,omitempty"`
+	// hash of the unixfs object holding the html body, set instead of htmlBody when stored
+	HtmlBodyHash string `protobuf:"bytes,23,opt,name=htmlBodyHash,proto3" json:"htmlBodyHash,omitempty"`
+	// hash of the Segments object holding the text body, set instead of textBodyHash
+	// when the body contains quoted text
+	TextSegmentsHash string `protobuf:"bytes,24,opt,name=textSegmentsHash,proto3" json:"textSegmentsHash,omitempty"`
+	// hash of the Segments object holding the html body, set instead of htmlBodyHash
+	// when the body contains quoted text
+	HtmlSegmentsHash string `protobuf:"bytes,25,opt,name=htmlSegmentsHash,proto3" json:"htmlSegmentsHash,omitempty"`
+	// headers removed from the headers map as they can be rebuilt exactly
+	// from the structured fields, restored when the email is retrieved
+	ElidedHeaders []ElidedHeader `protobuf:"bytes,26,rep,name=elidedHeaders,proto3" json:"elidedHeaders"`
+	// hash of the HeaderTemplate object holding the headers shared with other
+	// emails, which are removed from the headers map when stored
+	HeaderTemplateHash string `protobuf:"bytes,27,opt,name=headerTemplateHash,proto3" json:"headerTemplateHash,omitempty"`
+	// schema version the email was stored with, 0 for emails stored before
+	// versions were recorded
+	Version uint32 `protobuf:"varint,28,opt,name=version,proto3" json:"version,omitempty"`
+	// hash of the email object holding the parts of the message shared by
+	// every delivered copy. when set, this email is a delivery envelope only
+	// holding the trace headers and resent blocks of its copy
+	CoreHash string `protobuf:"bytes,29,opt,name=coreHash,proto3" json:"coreHash,omitempty"`
+	// set instead of every other field except version and summaryHash when
+	// the email is stored as the changes from the email it replies to
+	Delta *EmailDelta `protobuf:"bytes,30,opt,name=delta,proto3" json:"delta,omitempty"`
+	// hash of the Summary object listing the email, set when stored
+	SummaryHash string `protobuf:"bytes,31,opt,name=summaryHash,proto3" json:"summaryHash,omitempty"`
 }
 
 func (m *Email) Reset()         { *m = Email{} }
@@ -102,16 +153,12 @@ func (m *Email) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
 func (m *Email) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_Email.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
+	return b[:n], nil
 }
 func (m *Email) XXX_Merge(src proto.Message) {
 	xxx_messageInfo_Email.Merge(m, src)
@@ -209,1352 +256,11754 @@ func (m *Email) GetEmbeddedFiles() []EmbeddedFile {
 	return nil
 }
 
-type Attachment struct {
-	FileName    string `protobuf:"bytes,1,opt,name=fileName,proto3" json:"fileName,omitempty"`
-	ContentType string `protobuf:"bytes,2,opt,name=contentType,proto3" json:"contentType,omitempty"`
-	// hash of the unixfs object for the file
-	DataHash string `protobuf:"bytes,3,opt,name=dataHash,proto3" json:"dataHash,omitempty"`
+func (m *Email) GetTextCharset() string {
+	if m != nil {
+		return m.TextCharset
+	}
+	return ""
 }
 
-func (m *Attachment) Reset()         { *m = Attachment{} }
-func (m *Attachment) String() string { return proto.CompactTextString(m) }
-func (*Attachment) ProtoMessage()    {}
-func (*Attachment) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6175298cb4ed6faa, []int{2}
+func (m *Email) GetHtmlCharset() string {
+	if m != nil {
+		return m.HtmlCharset
+	}
+	return ""
 }
-func (m *Attachment) XXX_Unmarshal(b []byte) error {
-	return m.Unmarshal(b)
+
+func (m *Email) GetTextBodyRaw() []byte {
+	if m != nil {
+		return m.TextBodyRaw
+	}
+	return nil
 }
-func (m *Attachment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_Attachment.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+
+func (m *Email) GetHtmlBodyRaw() []byte {
+	if m != nil {
+		return m.HtmlBodyRaw
 	}
+	return nil
 }
-func (m *Attachment) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Attachment.Merge(m, src)
+
+func (m *Email) GetDateOffset() int32 {
+	if m != nil {
+		return m.DateOffset
+	}
+	return 0
 }
-func (m *Attachment) XXX_Size() int {
-	return m.Size()
+
+func (m *Email) GetRawDate() string {
+	if m != nil {
+		return m.RawDate
+	}
+	return ""
 }
-func (m *Attachment) XXX_DiscardUnknown() {
-	xxx_messageInfo_Attachment.DiscardUnknown(m)
+
+func (m *Email) GetDateInvalid() bool {
+	if m != nil {
+		return m.DateInvalid
+	}
+	return false
 }
 
-var xxx_messageInfo_Attachment proto.InternalMessageInfo
+func (m *Email) GetResentBlocks() []Resent {
+	if m != nil {
+		return m.ResentBlocks
+	}
+	return nil
+}
 
-func (m *Attachment) GetFileName() string {
+func (m *Email) GetTextBodyHash() string {
 	if m != nil {
-		return m.FileName
+		return m.TextBodyHash
 	}
 	return ""
 }
 
-func (m *Attachment) GetContentType() string {
+func (m *Email) GetHtmlBodyHash() string {
 	if m != nil {
-		return m.ContentType
+		return m.HtmlBodyHash
 	}
 	return ""
 }
 
-func (m *Attachment) GetDataHash() string {
+func (m *Email) GetTextSegmentsHash() string {
 	if m != nil {
-		return m.DataHash
+		return m.TextSegmentsHash
 	}
 	return ""
 }
 
-type EmbeddedFile struct {
-	ContentId   string `protobuf:"bytes,1,opt,name=contentId,proto3" json:"contentId,omitempty"`
-	ContentType string `protobuf:"bytes,2,opt,name=contentType,proto3" json:"contentType,omitempty"`
-	// hash of the unixfs object for the file
-	DataHash string `protobuf:"bytes,3,opt,name=dataHash,proto3" json:"dataHash,omitempty"`
+func (m *Email) GetHtmlSegmentsHash() string {
+	if m != nil {
+		return m.HtmlSegmentsHash
+	}
+	return ""
 }
 
-func (m *EmbeddedFile) Reset()         { *m = EmbeddedFile{} }
-func (m *EmbeddedFile) String() string { return proto.CompactTextString(m) }
-func (*EmbeddedFile) ProtoMessage()    {}
-func (*EmbeddedFile) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6175298cb4ed6faa, []int{3}
-}
-func (m *EmbeddedFile) XXX_Unmarshal(b []byte) error {
-	return m.Unmarshal(b)
-}
-func (m *EmbeddedFile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_EmbeddedFile.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+func (m *Email) GetElidedHeaders() []ElidedHeader {
+	if m != nil {
+		return m.ElidedHeaders
 	}
+	return nil
 }
-func (m *EmbeddedFile) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_EmbeddedFile.Merge(m, src)
-}
-func (m *EmbeddedFile) XXX_Size() int {
-	return m.Size()
-}
-func (m *EmbeddedFile) XXX_DiscardUnknown() {
-	xxx_messageInfo_EmbeddedFile.DiscardUnknown(m)
+
+func (m *Email) GetHeaderTemplateHash() string {
+	if m != nil {
+		return m.HeaderTemplateHash
+	}
+	return ""
 }
 
-var xxx_messageInfo_EmbeddedFile proto.InternalMessageInfo
+func (m *Email) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
 
-func (m *EmbeddedFile) GetContentId() string {
+func (m *Email) GetCoreHash() string {
 	if m != nil {
-		return m.ContentId
+		return m.CoreHash
 	}
 	return ""
 }
 
-func (m *EmbeddedFile) GetContentType() string {
+func (m *Email) GetDelta() *EmailDelta {
 	if m != nil {
-		return m.ContentType
+		return m.Delta
 	}
-	return ""
+	return nil
 }
 
-func (m *EmbeddedFile) GetDataHash() string {
+func (m *Email) GetSummaryHash() string {
 	if m != nil {
-		return m.DataHash
+		return m.SummaryHash
 	}
 	return ""
 }
 
-type Addresses struct {
-	Sender  *Address  `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
+// Summary holds what is needed to list an email without fetching its
+// bodies and attachments
+type Summary struct {
+	Subject string    `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
 	From    []Address `protobuf:"bytes,2,rep,name=from,proto3" json:"from"`
-	ReplyTo []Address `protobuf:"bytes,3,rep,name=replyTo,proto3" json:"replyTo"`
-	To      []Address `protobuf:"bytes,4,rep,name=to,proto3" json:"to"`
-	Cc      []Address `protobuf:"bytes,5,rep,name=cc,proto3" json:"cc"`
-	Bcc     []Address `protobuf:"bytes,6,rep,name=bcc,proto3" json:"bcc"`
+	To      []Address `protobuf:"bytes,3,rep,name=to,proto3" json:"to"`
+	Date    time.Time `protobuf:"bytes,4,opt,name=date,proto3,stdtime" json:"date"`
+	// size of the bodies, attachments and embedded files in bytes
+	MessageSize uint64 `protobuf:"varint,5,opt,name=messageSize,proto3" json:"messageSize,omitempty"`
+	Attachments uint32 `protobuf:"varint,6,opt,name=attachments,proto3" json:"attachments,omitempty"`
+	// start of the text of the email, with whitespace collapsed
+	Snippet string `protobuf:"bytes,7,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	// schema version the summary was stored with
+	Version uint32 `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
 }
 
-func (m *Addresses) Reset()         { *m = Addresses{} }
-func (m *Addresses) String() string { return proto.CompactTextString(m) }
-func (*Addresses) ProtoMessage()    {}
-func (*Addresses) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6175298cb4ed6faa, []int{4}
+func (m *Summary) Reset()         { *m = Summary{} }
+func (m *Summary) String() string { return proto.CompactTextString(m) }
+func (*Summary) ProtoMessage()    {}
+func (*Summary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{2}
 }
-func (m *Addresses) XXX_Unmarshal(b []byte) error {
+func (m *Summary) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Addresses) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_Addresses.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+func (m *Summary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
+	return b[:n], nil
 }
-func (m *Addresses) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Addresses.Merge(m, src)
+func (m *Summary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Summary.Merge(m, src)
 }
-func (m *Addresses) XXX_Size() int {
+func (m *Summary) XXX_Size() int {
 	return m.Size()
 }
-func (m *Addresses) XXX_DiscardUnknown() {
-	xxx_messageInfo_Addresses.DiscardUnknown(m)
+func (m *Summary) XXX_DiscardUnknown() {
+	xxx_messageInfo_Summary.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Addresses proto.InternalMessageInfo
+var xxx_messageInfo_Summary proto.InternalMessageInfo
 
-func (m *Addresses) GetSender() *Address {
+func (m *Summary) GetSubject() string {
 	if m != nil {
-		return m.Sender
+		return m.Subject
 	}
-	return nil
+	return ""
 }
 
-func (m *Addresses) GetFrom() []Address {
+func (m *Summary) GetFrom() []Address {
 	if m != nil {
 		return m.From
 	}
 	return nil
 }
 
-func (m *Addresses) GetReplyTo() []Address {
+func (m *Summary) GetTo() []Address {
 	if m != nil {
-		return m.ReplyTo
+		return m.To
 	}
 	return nil
 }
 
-func (m *Addresses) GetTo() []Address {
+func (m *Summary) GetDate() time.Time {
 	if m != nil {
-		return m.To
+		return m.Date
 	}
-	return nil
+	return time.Time{}
 }
 
-func (m *Addresses) GetCc() []Address {
+func (m *Summary) GetMessageSize() uint64 {
 	if m != nil {
-		return m.Cc
+		return m.MessageSize
 	}
-	return nil
+	return 0
 }
 
-func (m *Addresses) GetBcc() []Address {
+func (m *Summary) GetAttachments() uint32 {
 	if m != nil {
-		return m.Bcc
+		return m.Attachments
 	}
-	return nil
+	return 0
 }
 
-type Resent struct {
-	Addresses       Addresses `protobuf:"bytes,1,opt,name=addresses,proto3" json:"addresses"`
-	ResentDate      time.Time `protobuf:"bytes,2,opt,name=resentDate,proto3,stdtime" json:"resentDate"`
-	ResentMessageId string    `protobuf:"bytes,3,opt,name=resentMessageId,proto3" json:"resentMessageId,omitempty"`
+func (m *Summary) GetSnippet() string {
+	if m != nil {
+		return m.Snippet
+	}
+	return ""
 }
 
-func (m *Resent) Reset()         { *m = Resent{} }
-func (m *Resent) String() string { return proto.CompactTextString(m) }
-func (*Resent) ProtoMessage()    {}
-func (*Resent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6175298cb4ed6faa, []int{5}
+func (m *Summary) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
 }
-func (m *Resent) XXX_Unmarshal(b []byte) error {
+
+// EmailDelta is an email stored as a binary delta against the canonical
+// serialization of the retrieved email it replies to
+type EmailDelta struct {
+	// hash of the email the delta is against
+	BaseHash string `protobuf:"bytes,1,opt,name=baseHash,proto3" json:"baseHash,omitempty"`
+	// sha256 digest of the serialization the delta is against, so a change
+	// to how the base is retrieved is detected instead of giving a bad email
+	BaseDigest []byte `protobuf:"bytes,2,opt,name=baseDigest,proto3" json:"baseDigest,omitempty"`
+	// number of deltas applied to rebuild the email, including this one
+	Depth uint32    `protobuf:"varint,3,opt,name=depth,proto3" json:"depth,omitempty"`
+	Ops   []DeltaOp `protobuf:"bytes,4,rep,name=ops,proto3" json:"ops"`
+}
+
+func (m *EmailDelta) Reset()         { *m = EmailDelta{} }
+func (m *EmailDelta) String() string { return proto.CompactTextString(m) }
+func (*EmailDelta) ProtoMessage()    {}
+func (*EmailDelta) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{3}
+}
+func (m *EmailDelta) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Resent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_Resent.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+func (m *EmailDelta) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
+	return b[:n], nil
 }
-func (m *Resent) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Resent.Merge(m, src)
+func (m *EmailDelta) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EmailDelta.Merge(m, src)
 }
-func (m *Resent) XXX_Size() int {
+func (m *EmailDelta) XXX_Size() int {
 	return m.Size()
 }
-func (m *Resent) XXX_DiscardUnknown() {
-	xxx_messageInfo_Resent.DiscardUnknown(m)
+func (m *EmailDelta) XXX_DiscardUnknown() {
+	xxx_messageInfo_EmailDelta.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Resent proto.InternalMessageInfo
+var xxx_messageInfo_EmailDelta proto.InternalMessageInfo
 
-func (m *Resent) GetAddresses() Addresses {
+func (m *EmailDelta) GetBaseHash() string {
 	if m != nil {
-		return m.Addresses
+		return m.BaseHash
 	}
-	return Addresses{}
+	return ""
 }
 
-func (m *Resent) GetResentDate() time.Time {
+func (m *EmailDelta) GetBaseDigest() []byte {
 	if m != nil {
-		return m.ResentDate
+		return m.BaseDigest
 	}
-	return time.Time{}
+	return nil
 }
 
-func (m *Resent) GetResentMessageId() string {
+func (m *EmailDelta) GetDepth() uint32 {
 	if m != nil {
-		return m.ResentMessageId
+		return m.Depth
 	}
-	return ""
+	return 0
 }
 
-type Header struct {
-	Values map[string]Headers `protobuf:"bytes,1,rep,name=values,proto3" json:"values" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+func (m *EmailDelta) GetOps() []DeltaOp {
+	if m != nil {
+		return m.Ops
+	}
+	return nil
 }
 
-func (m *Header) Reset()         { *m = Header{} }
-func (m *Header) String() string { return proto.CompactTextString(m) }
-func (*Header) ProtoMessage()    {}
-func (*Header) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6175298cb4ed6faa, []int{6}
+// DeltaOp either inserts data, or copies length bytes of the base from offset
+type DeltaOp struct {
+	Offset uint64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length uint64 `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
+	Data   []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
 }
-func (m *Header) XXX_Unmarshal(b []byte) error {
+
+func (m *DeltaOp) Reset()         { *m = DeltaOp{} }
+func (m *DeltaOp) String() string { return proto.CompactTextString(m) }
+func (*DeltaOp) ProtoMessage()    {}
+func (*DeltaOp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{4}
+}
+func (m *DeltaOp) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Header) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_Header.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+func (m *DeltaOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
+	return b[:n], nil
 }
-func (m *Header) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Header.Merge(m, src)
+func (m *DeltaOp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeltaOp.Merge(m, src)
 }
-func (m *Header) XXX_Size() int {
+func (m *DeltaOp) XXX_Size() int {
 	return m.Size()
 }
-func (m *Header) XXX_DiscardUnknown() {
-	xxx_messageInfo_Header.DiscardUnknown(m)
+func (m *DeltaOp) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeltaOp.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Header proto.InternalMessageInfo
+var xxx_messageInfo_DeltaOp proto.InternalMessageInfo
 
-func (m *Header) GetValues() map[string]Headers {
+func (m *DeltaOp) GetOffset() uint64 {
 	if m != nil {
-		return m.Values
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *DeltaOp) GetLength() uint64 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+func (m *DeltaOp) GetData() []byte {
+	if m != nil {
+		return m.Data
 	}
 	return nil
 }
 
-type Headers struct {
-	Values []string `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+// HeaderTemplate is a group of headers repeated by many emails, such as those
+// added by the same mail system, stored once and linked from each email
+type HeaderTemplate struct {
+	// sorted by name so the same headers always give the same object
+	Headers []TemplateHeader `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers"`
 }
 
-func (m *Headers) Reset()         { *m = Headers{} }
-func (m *Headers) String() string { return proto.CompactTextString(m) }
-func (*Headers) ProtoMessage()    {}
-func (*Headers) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6175298cb4ed6faa, []int{7}
+func (m *HeaderTemplate) Reset()         { *m = HeaderTemplate{} }
+func (m *HeaderTemplate) String() string { return proto.CompactTextString(m) }
+func (*HeaderTemplate) ProtoMessage()    {}
+func (*HeaderTemplate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{5}
 }
-func (m *Headers) XXX_Unmarshal(b []byte) error {
+func (m *HeaderTemplate) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Headers) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_Headers.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+func (m *HeaderTemplate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
+	return b[:n], nil
 }
-func (m *Headers) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Headers.Merge(m, src)
+func (m *HeaderTemplate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeaderTemplate.Merge(m, src)
 }
-func (m *Headers) XXX_Size() int {
+func (m *HeaderTemplate) XXX_Size() int {
 	return m.Size()
 }
-func (m *Headers) XXX_DiscardUnknown() {
-	xxx_messageInfo_Headers.DiscardUnknown(m)
+func (m *HeaderTemplate) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeaderTemplate.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Headers proto.InternalMessageInfo
+var xxx_messageInfo_HeaderTemplate proto.InternalMessageInfo
 
-func (m *Headers) GetValues() []string {
+func (m *HeaderTemplate) GetHeaders() []TemplateHeader {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
+type TemplateHeader struct {
+	Name   string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Values []string `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (m *TemplateHeader) Reset()         { *m = TemplateHeader{} }
+func (m *TemplateHeader) String() string { return proto.CompactTextString(m) }
+func (*TemplateHeader) ProtoMessage()    {}
+func (*TemplateHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{6}
+}
+func (m *TemplateHeader) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TemplateHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *TemplateHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TemplateHeader.Merge(m, src)
+}
+func (m *TemplateHeader) XXX_Size() int {
+	return m.Size()
+}
+func (m *TemplateHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_TemplateHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TemplateHeader proto.InternalMessageInfo
+
+func (m *TemplateHeader) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TemplateHeader) GetValues() []string {
 	if m != nil {
 		return m.Values
 	}
 	return nil
 }
 
-// Values is basically an embedded slice in an email header
-type Values struct {
-	V []string `protobuf:"bytes,1,rep,name=v,proto3" json:"v,omitempty"`
+// ArchiveNode is a node of the hamt linking every email in an archive. emails
+// are linked by a stable key, and placed using the sha256 digest of the key,
+// one byte per level, so the same emails always give the same root
+type ArchiveNode struct {
+	// occupied slots, sorted by index
+	Slots []ArchiveSlot `protobuf:"bytes,1,rep,name=slots,proto3" json:"slots"`
+	// number of emails under this node
+	Count uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// schema version the node was stored with
+	Version uint32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
 }
 
-func (m *Values) Reset()         { *m = Values{} }
-func (m *Values) String() string { return proto.CompactTextString(m) }
-func (*Values) ProtoMessage()    {}
-func (*Values) Descriptor() ([]byte, []int) {
+func (m *ArchiveNode) Reset()         { *m = ArchiveNode{} }
+func (m *ArchiveNode) String() string { return proto.CompactTextString(m) }
+func (*ArchiveNode) ProtoMessage()    {}
+func (*ArchiveNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{7}
+}
+func (m *ArchiveNode) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ArchiveNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *ArchiveNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArchiveNode.Merge(m, src)
+}
+func (m *ArchiveNode) XXX_Size() int {
+	return m.Size()
+}
+func (m *ArchiveNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArchiveNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ArchiveNode proto.InternalMessageInfo
+
+func (m *ArchiveNode) GetSlots() []ArchiveSlot {
+	if m != nil {
+		return m.Slots
+	}
+	return nil
+}
+
+func (m *ArchiveNode) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ArchiveNode) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type ArchiveSlot struct {
+	// byte of the key digest at the depth of the node
+	Index uint32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// emails in the slot sorted by key, set until the slot is split
+	Links []ArchiveLink `protobuf:"bytes,2,rep,name=links,proto3" json:"links"`
+	// hash of the ArchiveNode the slot was split into
+	ChildHash string `protobuf:"bytes,3,opt,name=childHash,proto3" json:"childHash,omitempty"`
+}
+
+func (m *ArchiveSlot) Reset()         { *m = ArchiveSlot{} }
+func (m *ArchiveSlot) String() string { return proto.CompactTextString(m) }
+func (*ArchiveSlot) ProtoMessage()    {}
+func (*ArchiveSlot) Descriptor() ([]byte, []int) {
 	return fileDescriptor_6175298cb4ed6faa, []int{8}
 }
-func (m *Values) XXX_Unmarshal(b []byte) error {
+func (m *ArchiveSlot) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Values) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_Values.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+func (m *ArchiveSlot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
+	return b[:n], nil
 }
-func (m *Values) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Values.Merge(m, src)
+func (m *ArchiveSlot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArchiveSlot.Merge(m, src)
 }
-func (m *Values) XXX_Size() int {
+func (m *ArchiveSlot) XXX_Size() int {
 	return m.Size()
 }
-func (m *Values) XXX_DiscardUnknown() {
-	xxx_messageInfo_Values.DiscardUnknown(m)
+func (m *ArchiveSlot) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArchiveSlot.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Values proto.InternalMessageInfo
+var xxx_messageInfo_ArchiveSlot proto.InternalMessageInfo
 
-func (m *Values) GetV() []string {
+func (m *ArchiveSlot) GetIndex() uint32 {
 	if m != nil {
-		return m.V
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ArchiveSlot) GetLinks() []ArchiveLink {
+	if m != nil {
+		return m.Links
 	}
 	return nil
 }
 
-type Address struct {
-	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Address string `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+func (m *ArchiveSlot) GetChildHash() string {
+	if m != nil {
+		return m.ChildHash
+	}
+	return ""
 }
 
-func (m *Address) Reset()         { *m = Address{} }
-func (m *Address) String() string { return proto.CompactTextString(m) }
-func (*Address) ProtoMessage()    {}
-func (*Address) Descriptor() ([]byte, []int) {
+type ArchiveLink struct {
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// hash of the email
+	Hash string `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	// set when the email is stored in the chunked format
+	Chunked bool `protobuf:"varint,3,opt,name=chunked,proto3" json:"chunked,omitempty"`
+}
+
+func (m *ArchiveLink) Reset()         { *m = ArchiveLink{} }
+func (m *ArchiveLink) String() string { return proto.CompactTextString(m) }
+func (*ArchiveLink) ProtoMessage()    {}
+func (*ArchiveLink) Descriptor() ([]byte, []int) {
 	return fileDescriptor_6175298cb4ed6faa, []int{9}
 }
-func (m *Address) XXX_Unmarshal(b []byte) error {
+func (m *ArchiveLink) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Address) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_Address.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+func (m *ArchiveLink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
+	return b[:n], nil
 }
-func (m *Address) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Address.Merge(m, src)
+func (m *ArchiveLink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArchiveLink.Merge(m, src)
 }
-func (m *Address) XXX_Size() int {
+func (m *ArchiveLink) XXX_Size() int {
 	return m.Size()
 }
-func (m *Address) XXX_DiscardUnknown() {
-	xxx_messageInfo_Address.DiscardUnknown(m)
+func (m *ArchiveLink) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArchiveLink.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Address proto.InternalMessageInfo
+var xxx_messageInfo_ArchiveLink proto.InternalMessageInfo
 
-func (m *Address) GetName() string {
+func (m *ArchiveLink) GetKey() string {
 	if m != nil {
-		return m.Name
+		return m.Key
 	}
 	return ""
 }
 
-func (m *Address) GetAddress() string {
+func (m *ArchiveLink) GetHash() string {
 	if m != nil {
-		return m.Address
+		return m.Hash
 	}
 	return ""
 }
 
-func init() {
-	proto.RegisterType((*ChunkedEmail)(nil), "pb.ChunkedEmail")
-	proto.RegisterMapType((map[int32]string)(nil), "pb.ChunkedEmail.PartsEntry")
-	proto.RegisterType((*Email)(nil), "pb.Email")
-	proto.RegisterType((*Attachment)(nil), "pb.Attachment")
-	proto.RegisterType((*EmbeddedFile)(nil), "pb.EmbeddedFile")
-	proto.RegisterType((*Addresses)(nil), "pb.Addresses")
-	proto.RegisterType((*Resent)(nil), "pb.Resent")
-	proto.RegisterType((*Header)(nil), "pb.Header")
-	proto.RegisterMapType((map[string]Headers)(nil), "pb.Header.ValuesEntry")
-	proto.RegisterType((*Headers)(nil), "pb.Headers")
-	proto.RegisterType((*Values)(nil), "pb.Values")
-	proto.RegisterType((*Address)(nil), "pb.Address")
+func (m *ArchiveLink) GetChunked() bool {
+	if m != nil {
+		return m.Chunked
+	}
+	return false
 }
 
-func init() { proto.RegisterFile("email.proto", fileDescriptor_6175298cb4ed6faa) }
-
-var fileDescriptor_6175298cb4ed6faa = []byte{
-	// 742 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x4b, 0x6b, 0x14, 0x4b,
-	0x14, 0x9e, 0x9a, 0x47, 0x4f, 0xfa, 0xf4, 0xe4, 0xde, 0x50, 0x5c, 0x42, 0x31, 0xf7, 0x32, 0x99,
-	0x74, 0xb8, 0x30, 0x28, 0x76, 0x48, 0x14, 0x0d, 0xe2, 0xc6, 0x31, 0x09, 0xc9, 0x42, 0x91, 0x26,
-	0xb8, 0xef, 0xc7, 0x99, 0x47, 0x32, 0xfd, 0xa0, 0xab, 0x26, 0x38, 0xb8, 0x77, 0x9d, 0xff, 0xe1,
-	0x1f, 0xc9, 0x32, 0x4b, 0x57, 0x2a, 0xc9, 0xc6, 0x9d, 0x7f, 0x41, 0xaa, 0xba, 0x7a, 0xba, 0x23,
-	0x06, 0x14, 0x77, 0x75, 0xbe, 0xef, 0x3b, 0x75, 0x8a, 0xaf, 0xce, 0x39, 0x60, 0x61, 0xe4, 0x4d,
-	0x67, 0x4e, 0x9a, 0x25, 0x22, 0xa1, 0xf5, 0xd4, 0xef, 0x3e, 0x18, 0x4f, 0xc5, 0x64, 0xee, 0x3b,
-	0x41, 0x12, 0x6d, 0x8f, 0x93, 0x71, 0xb2, 0xad, 0x28, 0x7f, 0x3e, 0x52, 0x91, 0x0a, 0xd4, 0x29,
-	0x4f, 0xe9, 0x6e, 0x8c, 0x93, 0x64, 0x3c, 0xc3, 0x52, 0x25, 0xa6, 0x11, 0x72, 0xe1, 0x45, 0x69,
-	0x2e, 0xb0, 0xdf, 0x41, 0xe7, 0xc5, 0x64, 0x1e, 0x9f, 0x61, 0x78, 0x20, 0x2b, 0xd1, 0x1d, 0x68,
-	0xa5, 0x5e, 0x26, 0x38, 0x23, 0xfd, 0xc6, 0xc0, 0xda, 0xfd, 0xd7, 0x49, 0x7d, 0xa7, 0x2a, 0x70,
-	0x5e, 0x4b, 0xf6, 0x20, 0x16, 0xd9, 0xc2, 0xcd, 0x95, 0xdd, 0x3d, 0x80, 0x12, 0xa4, 0x6b, 0xd0,
-	0x38, 0xc3, 0x05, 0x23, 0x7d, 0x32, 0x68, 0xb9, 0xf2, 0x48, 0xff, 0x81, 0xd6, 0xb9, 0x37, 0x9b,
-	0x23, 0xab, 0xf7, 0xc9, 0xc0, 0x74, 0xf3, 0xe0, 0x69, 0x7d, 0x8f, 0xd8, 0x5f, 0x1b, 0xd0, 0xca,
-	0xcb, 0xde, 0x83, 0xf6, 0x04, 0xbd, 0x10, 0x33, 0xae, 0x32, 0xad, 0x5d, 0x90, 0x85, 0x8f, 0x14,
-	0x34, 0x6c, 0x5e, 0x7e, 0xda, 0xa8, 0xb9, 0x85, 0x80, 0x32, 0x68, 0xf3, 0xb9, 0x7f, 0x8a, 0x81,
-	0xd0, 0x37, 0x16, 0x21, 0xdd, 0x01, 0xd3, 0x0b, 0xc3, 0x0c, 0x39, 0x47, 0xce, 0x1a, 0xea, 0x9e,
-	0x55, 0x79, 0xcf, 0xf3, 0x02, 0xd4, 0x57, 0x95, 0x2a, 0xba, 0x07, 0xcd, 0xd0, 0x13, 0xc8, 0x9a,
-	0x4a, 0xdd, 0x75, 0x72, 0xbf, 0x9c, 0xc2, 0x2f, 0xe7, 0xa4, 0xf0, 0x6b, 0xb8, 0x22, 0x53, 0x2f,
-	0x3e, 0x6f, 0x10, 0x57, 0x65, 0xd0, 0xff, 0xc0, 0x8c, 0x90, 0x73, 0x6f, 0x8c, 0xc7, 0xfb, 0xac,
-	0xa5, 0x1e, 0x52, 0x02, 0x92, 0x9d, 0xc6, 0x2e, 0xa6, 0xb3, 0xc5, 0x49, 0xc2, 0x8c, 0x7e, 0x43,
-	0xb2, 0x4b, 0x80, 0xf6, 0x00, 0x32, 0x1c, 0x61, 0x86, 0x71, 0x80, 0x9c, 0xb5, 0x15, 0x5d, 0x41,
-	0xa8, 0x0d, 0x46, 0x86, 0x1c, 0x63, 0xc1, 0x56, 0x4a, 0x37, 0x5c, 0x85, 0xb8, 0x9a, 0xa1, 0x5d,
-	0x58, 0x99, 0x88, 0x68, 0x36, 0x4c, 0xc2, 0x05, 0x03, 0x55, 0x7e, 0x19, 0x4b, 0x4e, 0xe0, 0x5b,
-	0xa1, 0x38, 0x2b, 0xe7, 0x8a, 0x98, 0x3e, 0x06, 0xcb, 0x13, 0xc2, 0x0b, 0x26, 0x11, 0xc6, 0x82,
-	0xb3, 0x8e, 0xfa, 0xe7, 0xbf, 0x94, 0x4d, 0x4b, 0x58, 0xfb, 0x54, 0x15, 0xd2, 0x67, 0xb0, 0x8a,
-	0x91, 0x8f, 0x61, 0x88, 0xe1, 0xe1, 0x74, 0x86, 0x9c, 0xad, 0xaa, 0xcc, 0x35, 0x99, 0x79, 0x50,
-	0x21, 0x74, 0xee, 0x6d, 0xb1, 0x3d, 0x02, 0x28, 0xaf, 0x97, 0xef, 0x1b, 0x4d, 0x67, 0xf8, 0xca,
-	0x8b, 0x50, 0xfd, 0xb7, 0xe9, 0x2e, 0x63, 0xda, 0x07, 0x2b, 0x48, 0x62, 0x81, 0xb1, 0x38, 0x59,
-	0xa4, 0x45, 0xd3, 0x54, 0x21, 0x99, 0x1d, 0x7a, 0xc2, 0x3b, 0xf2, 0xf8, 0x44, 0xfd, 0xb2, 0xe9,
-	0x2e, 0x63, 0xfb, 0x14, 0x3a, 0xd5, 0xc7, 0xc8, 0x7f, 0xd0, 0xa9, 0xc7, 0xa1, 0x2e, 0x55, 0x02,
-	0x7f, 0x58, 0xeb, 0x1b, 0x01, 0x73, 0xd9, 0x5a, 0x74, 0x0b, 0x0c, 0x8e, 0x71, 0x88, 0x99, 0xee,
-	0x60, 0xab, 0xd2, 0x79, 0xae, 0xa6, 0xe8, 0xff, 0xd0, 0x1c, 0x65, 0x49, 0xc4, 0xea, 0xca, 0xbb,
-	0xaa, 0x44, 0xdb, 0xa6, 0x68, 0x7a, 0x1f, 0xda, 0x99, 0xee, 0x9d, 0xc6, 0x5d, 0xca, 0x42, 0x41,
-	0x37, 0xa1, 0x2e, 0x12, 0xd6, 0xbc, 0x4b, 0x57, 0x17, 0x4a, 0x12, 0x04, 0xac, 0x75, 0xa7, 0x24,
-	0x08, 0xe8, 0x16, 0x34, 0xfc, 0x20, 0x50, 0xad, 0xfa, 0x53, 0x8d, 0x64, 0xed, 0x0f, 0x04, 0x8c,
-	0xbc, 0x0d, 0x6f, 0xcf, 0x1a, 0xf9, 0xa5, 0x59, 0xdb, 0x97, 0x5d, 0x2f, 0x93, 0xf7, 0xe5, 0xc4,
-	0xd5, 0x7f, 0x63, 0xe2, 0x2a, 0x79, 0x74, 0x00, 0x7f, 0xe7, 0xd1, 0x4b, 0x3d, 0x6c, 0xa1, 0xfe,
-	0x98, 0x1f, 0x61, 0xfb, 0x3d, 0x01, 0x23, 0x5f, 0x21, 0xf4, 0x11, 0x18, 0x6a, 0xed, 0x14, 0x7b,
-	0x6d, 0xbd, 0x5c, 0x2f, 0xce, 0x1b, 0x45, 0xa8, 0xed, 0xa5, 0xdf, 0xac, 0xb5, 0xdd, 0x43, 0xb0,
-	0x2a, 0x64, 0x75, 0xb5, 0x99, 0xf9, 0x6a, 0xdb, 0xac, 0xae, 0x36, 0x6d, 0x5b, 0x7e, 0x2b, 0xaf,
-	0xee, 0xb9, 0x4d, 0x68, 0x6b, 0x94, 0xae, 0xdf, 0x7a, 0x88, 0x59, 0x94, 0xb2, 0xd7, 0xc1, 0xc8,
-	0x4b, 0xd1, 0x0e, 0x90, 0x73, 0x4d, 0x92, 0x73, 0xfb, 0x09, 0xb4, 0xb5, 0xa3, 0x94, 0x42, 0x33,
-	0x2e, 0x07, 0x46, 0x9d, 0xe5, 0x2e, 0xd4, 0xfe, 0x16, 0xbb, 0x50, 0x87, 0x43, 0x76, 0x79, 0xdd,
-	0x23, 0x57, 0xd7, 0x3d, 0xf2, 0xe5, 0xba, 0x47, 0x2e, 0x6e, 0x7a, 0xb5, 0xab, 0x9b, 0x5e, 0xed,
-	0xe3, 0x4d, 0xaf, 0xe6, 0x1b, 0xca, 0xea, 0x87, 0xdf, 0x03, 0x00, 0x00, 0xff, 0xff, 0x9a, 0x13,
-	0xce, 0x2e, 0x5c, 0x06, 0x00, 0x00,
+// Folder is a mailbox folder, holding its messages in order and linking its
+// subfolders, so a folder tree such as an imap account can be archived
+type Folder struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// messages in the folder, in mailbox order
+	Entries []FolderEntry `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries"`
+	// subfolders sorted by name
+	Folders []FolderLink `protobuf:"bytes,3,rep,name=folders,proto3" json:"folders"`
+	// schema version the folder was stored with
+	Version uint32 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
 }
 
-func (m *ChunkedEmail) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (m *Folder) Reset()         { *m = Folder{} }
+func (m *Folder) String() string { return proto.CompactTextString(m) }
+func (*Folder) ProtoMessage()    {}
+func (*Folder) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{10}
+}
+func (m *Folder) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Folder) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
+}
+func (m *Folder) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Folder.Merge(m, src)
+}
+func (m *Folder) XXX_Size() int {
+	return m.Size()
+}
+func (m *Folder) XXX_DiscardUnknown() {
+	xxx_messageInfo_Folder.DiscardUnknown(m)
 }
 
-func (m *ChunkedEmail) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var xxx_messageInfo_Folder proto.InternalMessageInfo
+
+func (m *Folder) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
 }
 
-func (m *ChunkedEmail) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Parts) > 0 {
-		for k := range m.Parts {
-			v := m.Parts[k]
-			baseI := i
-			i -= len(v)
-			copy(dAtA[i:], v)
-			i = encodeVarintEmail(dAtA, i, uint64(len(v)))
-			i--
-			dAtA[i] = 0x12
-			i = encodeVarintEmail(dAtA, i, uint64(k))
-			i--
-			dAtA[i] = 0x8
-			i = encodeVarintEmail(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0xa
-		}
+func (m *Folder) GetEntries() []FolderEntry {
+	if m != nil {
+		return m.Entries
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *Email) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (m *Folder) GetFolders() []FolderLink {
+	if m != nil {
+		return m.Folders
+	}
+	return nil
+}
+
+func (m *Folder) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type FolderEntry struct {
+	// hash of the email
+	Hash string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	// set when the email is stored in the chunked format
+	Chunked bool `protobuf:"varint,2,opt,name=chunked,proto3" json:"chunked,omitempty"`
+	// when the message was delivered to the mailbox
+	Received time.Time `protobuf:"bytes,3,opt,name=received,proto3,stdtime" json:"received"`
+	// flags such as \Seen and \Flagged
+	Flags []string `protobuf:"bytes,4,rep,name=flags,proto3" json:"flags,omitempty"`
+	// path the message was imported from
+	SourcePath string `protobuf:"bytes,5,opt,name=sourcePath,proto3" json:"sourcePath,omitempty"`
+}
+
+func (m *FolderEntry) Reset()         { *m = FolderEntry{} }
+func (m *FolderEntry) String() string { return proto.CompactTextString(m) }
+func (*FolderEntry) ProtoMessage()    {}
+func (*FolderEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{11}
+}
+func (m *FolderEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FolderEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
+}
+func (m *FolderEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FolderEntry.Merge(m, src)
+}
+func (m *FolderEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *FolderEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_FolderEntry.DiscardUnknown(m)
 }
 
-func (m *Email) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var xxx_messageInfo_FolderEntry proto.InternalMessageInfo
+
+func (m *FolderEntry) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
 }
 
-func (m *Email) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.EmbeddedFiles) > 0 {
-		for iNdEx := len(m.EmbeddedFiles) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.EmbeddedFiles[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintEmail(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x6a
-		}
+func (m *FolderEntry) GetChunked() bool {
+	if m != nil {
+		return m.Chunked
 	}
-	if len(m.Attachments) > 0 {
-		for iNdEx := len(m.Attachments) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Attachments[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintEmail(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x62
-		}
+	return false
+}
+
+func (m *FolderEntry) GetReceived() time.Time {
+	if m != nil {
+		return m.Received
 	}
-	if len(m.TextBody) > 0 {
-		i -= len(m.TextBody)
-		copy(dAtA[i:], m.TextBody)
-		i = encodeVarintEmail(dAtA, i, uint64(len(m.TextBody)))
-		i--
-		dAtA[i] = 0x5a
+	return time.Time{}
+}
+
+func (m *FolderEntry) GetFlags() []string {
+	if m != nil {
+		return m.Flags
 	}
-	if len(m.HtmlBody) > 0 {
-		i -= len(m.HtmlBody)
-		copy(dAtA[i:], m.HtmlBody)
-		i = encodeVarintEmail(dAtA, i, uint64(len(m.HtmlBody)))
-		i--
-		dAtA[i] = 0x52
+	return nil
+}
+
+func (m *FolderEntry) GetSourcePath() string {
+	if m != nil {
+		return m.SourcePath
 	}
-	if m.Resent != nil {
-		{
-			size, err := m.Resent.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintEmail(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x42
+	return ""
+}
+
+type FolderLink struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// hash of the Folder object
+	Hash string `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (m *FolderLink) Reset()         { *m = FolderLink{} }
+func (m *FolderLink) String() string { return proto.CompactTextString(m) }
+func (*FolderLink) ProtoMessage()    {}
+func (*FolderLink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{12}
+}
+func (m *FolderLink) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FolderLink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	if len(m.References) > 0 {
-		for iNdEx := len(m.References) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.References[iNdEx])
-			copy(dAtA[i:], m.References[iNdEx])
-			i = encodeVarintEmail(dAtA, i, uint64(len(m.References[iNdEx])))
-			i--
-			dAtA[i] = 0x3a
-		}
+	return b[:n], nil
+}
+func (m *FolderLink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FolderLink.Merge(m, src)
+}
+func (m *FolderLink) XXX_Size() int {
+	return m.Size()
+}
+func (m *FolderLink) XXX_DiscardUnknown() {
+	xxx_messageInfo_FolderLink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FolderLink proto.InternalMessageInfo
+
+func (m *FolderLink) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	if len(m.InReplyTo) > 0 {
-		for iNdEx := len(m.InReplyTo) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.InReplyTo[iNdEx])
-			copy(dAtA[i:], m.InReplyTo[iNdEx])
-			i = encodeVarintEmail(dAtA, i, uint64(len(m.InReplyTo[iNdEx])))
-			i--
-			dAtA[i] = 0x32
-		}
+	return ""
+}
+
+func (m *FolderLink) GetHash() string {
+	if m != nil {
+		return m.Hash
 	}
-	if len(m.MessageID) > 0 {
-		i -= len(m.MessageID)
-		copy(dAtA[i:], m.MessageID)
-		i = encodeVarintEmail(dAtA, i, uint64(len(m.MessageID)))
-		i--
-		dAtA[i] = 0x2a
+	return ""
+}
+
+// Thread is a conversation, linking its messages as a tree built from
+// their references
+type Thread struct {
+	// subject of the conversation, without reply and forward prefixes
+	Subject string     `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Root    ThreadNode `protobuf:"bytes,2,opt,name=root,proto3" json:"root"`
+	// number of stored messages in the thread
+	Count uint64 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	// schema version the thread was stored with
+	Version uint32 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (m *Thread) Reset()         { *m = Thread{} }
+func (m *Thread) String() string { return proto.CompactTextString(m) }
+func (*Thread) ProtoMessage()    {}
+func (*Thread) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{13}
+}
+func (m *Thread) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Thread) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	n2, err2 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Date, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Date):])
-	if err2 != nil {
-		return 0, err2
+	return b[:n], nil
+}
+func (m *Thread) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Thread.Merge(m, src)
+}
+func (m *Thread) XXX_Size() int {
+	return m.Size()
+}
+func (m *Thread) XXX_DiscardUnknown() {
+	xxx_messageInfo_Thread.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Thread proto.InternalMessageInfo
+
+func (m *Thread) GetSubject() string {
+	if m != nil {
+		return m.Subject
 	}
-	i -= n2
-	i = encodeVarintEmail(dAtA, i, uint64(n2))
-	i--
-	dAtA[i] = 0x22
-	{
-		size, err := m.Addresses.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintEmail(dAtA, i, uint64(size))
+	return ""
+}
+
+func (m *Thread) GetRoot() ThreadNode {
+	if m != nil {
+		return m.Root
 	}
-	i--
-	dAtA[i] = 0x1a
-	if len(m.Subject) > 0 {
-		i -= len(m.Subject)
-		copy(dAtA[i:], m.Subject)
-		i = encodeVarintEmail(dAtA, i, uint64(len(m.Subject)))
-		i--
-		dAtA[i] = 0x12
+	return ThreadNode{}
+}
+
+func (m *Thread) GetCount() uint64 {
+	if m != nil {
+		return m.Count
 	}
-	{
-		size, err := m.Headers.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintEmail(dAtA, i, uint64(size))
+	return 0
+}
+
+func (m *Thread) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
 	}
-	i--
-	dAtA[i] = 0xa
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *Attachment) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+type ThreadNode struct {
+	// hash of the email, empty for messages which are referenced but not stored
+	Hash      string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	MessageID string `protobuf:"bytes,2,opt,name=messageID,proto3" json:"messageID,omitempty"`
+	// replies, ordered by date
+	Children []ThreadNode `protobuf:"bytes,3,rep,name=children,proto3" json:"children"`
+}
+
+func (m *ThreadNode) Reset()         { *m = ThreadNode{} }
+func (m *ThreadNode) String() string { return proto.CompactTextString(m) }
+func (*ThreadNode) ProtoMessage()    {}
+func (*ThreadNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{14}
+}
+func (m *ThreadNode) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ThreadNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
 }
-
-func (m *Attachment) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *ThreadNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThreadNode.Merge(m, src)
+}
+func (m *ThreadNode) XXX_Size() int {
+	return m.Size()
+}
+func (m *ThreadNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThreadNode.DiscardUnknown(m)
 }
 
-func (m *Attachment) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.DataHash) > 0 {
-		i -= len(m.DataHash)
-		copy(dAtA[i:], m.DataHash)
-		i = encodeVarintEmail(dAtA, i, uint64(len(m.DataHash)))
-		i--
-		dAtA[i] = 0x1a
+var xxx_messageInfo_ThreadNode proto.InternalMessageInfo
+
+func (m *ThreadNode) GetHash() string {
+	if m != nil {
+		return m.Hash
 	}
-	if len(m.ContentType) > 0 {
-		i -= len(m.ContentType)
-		copy(dAtA[i:], m.ContentType)
-		i = encodeVarintEmail(dAtA, i, uint64(len(m.ContentType)))
-		i--
-		dAtA[i] = 0x12
+	return ""
+}
+
+func (m *ThreadNode) GetMessageID() string {
+	if m != nil {
+		return m.MessageID
 	}
-	if len(m.FileName) > 0 {
-		i -= len(m.FileName)
-		copy(dAtA[i:], m.FileName)
-		i = encodeVarintEmail(dAtA, i, uint64(len(m.FileName)))
-		i--
-		dAtA[i] = 0xa
+	return ""
+}
+
+func (m *ThreadNode) GetChildren() []ThreadNode {
+	if m != nil {
+		return m.Children
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *EmbeddedFile) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+// MessageIndex maps message ids to the stored copies of each message, so
+// messages ingested from several mailboxes can be found and deduplicated
+type MessageIndex struct {
+	// sorted by message id
+	Entries []MessageIndexEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries"`
+	// schema version the index was stored with
+	Version uint32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (m *MessageIndex) Reset()         { *m = MessageIndex{} }
+func (m *MessageIndex) String() string { return proto.CompactTextString(m) }
+func (*MessageIndex) ProtoMessage()    {}
+func (*MessageIndex) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{15}
+}
+func (m *MessageIndex) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MessageIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
+}
+func (m *MessageIndex) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageIndex.Merge(m, src)
+}
+func (m *MessageIndex) XXX_Size() int {
+	return m.Size()
+}
+func (m *MessageIndex) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageIndex.DiscardUnknown(m)
 }
 
-func (m *EmbeddedFile) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var xxx_messageInfo_MessageIndex proto.InternalMessageInfo
+
+func (m *MessageIndex) GetEntries() []MessageIndexEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
 }
 
-func (m *EmbeddedFile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.DataHash) > 0 {
-		i -= len(m.DataHash)
-		copy(dAtA[i:], m.DataHash)
-		i = encodeVarintEmail(dAtA, i, uint64(len(m.DataHash)))
-		i--
-		dAtA[i] = 0x1a
+func (m *MessageIndex) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
 	}
-	if len(m.ContentType) > 0 {
-		i -= len(m.ContentType)
-		copy(dAtA[i:], m.ContentType)
-		i = encodeVarintEmail(dAtA, i, uint64(len(m.ContentType)))
-		i--
-		dAtA[i] = 0x12
+	return 0
+}
+
+type MessageIndexEntry struct {
+	MessageID string `protobuf:"bytes,1,opt,name=messageID,proto3" json:"messageID,omitempty"`
+	// stored copies of the message, in the order they were ingested
+	Copies []IndexedCopy `protobuf:"bytes,2,rep,name=copies,proto3" json:"copies"`
+}
+
+func (m *MessageIndexEntry) Reset()         { *m = MessageIndexEntry{} }
+func (m *MessageIndexEntry) String() string { return proto.CompactTextString(m) }
+func (*MessageIndexEntry) ProtoMessage()    {}
+func (*MessageIndexEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{16}
+}
+func (m *MessageIndexEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MessageIndexEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	if len(m.ContentId) > 0 {
-		i -= len(m.ContentId)
-		copy(dAtA[i:], m.ContentId)
-		i = encodeVarintEmail(dAtA, i, uint64(len(m.ContentId)))
-		i--
-		dAtA[i] = 0xa
+	return b[:n], nil
+}
+func (m *MessageIndexEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageIndexEntry.Merge(m, src)
+}
+func (m *MessageIndexEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *MessageIndexEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageIndexEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessageIndexEntry proto.InternalMessageInfo
+
+func (m *MessageIndexEntry) GetMessageID() string {
+	if m != nil {
+		return m.MessageID
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *Addresses) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (m *MessageIndexEntry) GetCopies() []IndexedCopy {
+	if m != nil {
+		return m.Copies
+	}
+	return nil
+}
+
+type IndexedCopy struct {
+	// hash of the email
+	Hash string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	// hex encoded sha256 digest of the raw message
+	RawDigest string `protobuf:"bytes,2,opt,name=rawDigest,proto3" json:"rawDigest,omitempty"`
+	// hex encoded sha256 digest of the email without its trace headers
+	ContentDigest string `protobuf:"bytes,3,opt,name=contentDigest,proto3" json:"contentDigest,omitempty"`
+	// hash of the earlier copy this copy only differs from in trace headers,
+	// which is the core of the envelope stored for this copy
+	VariantOf string `protobuf:"bytes,4,opt,name=variantOf,proto3" json:"variantOf,omitempty"`
+}
+
+func (m *IndexedCopy) Reset()         { *m = IndexedCopy{} }
+func (m *IndexedCopy) String() string { return proto.CompactTextString(m) }
+func (*IndexedCopy) ProtoMessage()    {}
+func (*IndexedCopy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{17}
+}
+func (m *IndexedCopy) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *IndexedCopy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
+}
+func (m *IndexedCopy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IndexedCopy.Merge(m, src)
+}
+func (m *IndexedCopy) XXX_Size() int {
+	return m.Size()
+}
+func (m *IndexedCopy) XXX_DiscardUnknown() {
+	xxx_messageInfo_IndexedCopy.DiscardUnknown(m)
 }
 
-func (m *Addresses) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var xxx_messageInfo_IndexedCopy proto.InternalMessageInfo
+
+func (m *IndexedCopy) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
 }
 
-func (m *Addresses) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Bcc) > 0 {
-		for iNdEx := len(m.Bcc) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Bcc[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintEmail(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x32
-		}
-	}
-	if len(m.Cc) > 0 {
-		for iNdEx := len(m.Cc) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Cc[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintEmail(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x2a
-		}
-	}
-	if len(m.To) > 0 {
-		for iNdEx := len(m.To) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.To[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintEmail(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x22
-		}
-	}
-	if len(m.ReplyTo) > 0 {
-		for iNdEx := len(m.ReplyTo) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.ReplyTo[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintEmail(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x1a
-		}
-	}
-	if len(m.From) > 0 {
-		for iNdEx := len(m.From) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.From[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintEmail(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
-		}
-	}
-	if m.Sender != nil {
-		{
-			size, err := m.Sender.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintEmail(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0xa
+func (m *IndexedCopy) GetRawDigest() string {
+	if m != nil {
+		return m.RawDigest
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *Resent) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *IndexedCopy) GetContentDigest() string {
+	if m != nil {
+		return m.ContentDigest
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *Resent) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *IndexedCopy) GetVariantOf() string {
+	if m != nil {
+		return m.VariantOf
+	}
+	return ""
 }
 
-func (m *Resent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.ResentMessageId) > 0 {
-		i -= len(m.ResentMessageId)
-		copy(dAtA[i:], m.ResentMessageId)
-		i = encodeVarintEmail(dAtA, i, uint64(len(m.ResentMessageId)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	n6, err6 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.ResentDate, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.ResentDate):])
-	if err6 != nil {
-		return 0, err6
-	}
-	i -= n6
-	i = encodeVarintEmail(dAtA, i, uint64(n6))
-	i--
-	dAtA[i] = 0x12
-	{
-		size, err := m.Addresses.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintEmail(dAtA, i, uint64(size))
-	}
-	i--
-	dAtA[i] = 0xa
-	return len(dAtA) - i, nil
+// ElidedHeader is a header removed from the headers map when stored
+type ElidedHeader struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// how the addresses of an address header were formatted
+	Style int32 `protobuf:"varint,2,opt,name=style,proto3" json:"style,omitempty"`
 }
 
-func (m *Header) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (m *ElidedHeader) Reset()         { *m = ElidedHeader{} }
+func (m *ElidedHeader) String() string { return proto.CompactTextString(m) }
+func (*ElidedHeader) ProtoMessage()    {}
+func (*ElidedHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{18}
+}
+func (m *ElidedHeader) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ElidedHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
 }
-
-func (m *Header) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *ElidedHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ElidedHeader.Merge(m, src)
+}
+func (m *ElidedHeader) XXX_Size() int {
+	return m.Size()
+}
+func (m *ElidedHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_ElidedHeader.DiscardUnknown(m)
 }
 
-func (m *Header) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Values) > 0 {
-		for k := range m.Values {
-			v := m.Values[k]
-			baseI := i
-			{
-				size, err := (&v).MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintEmail(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintEmail(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintEmail(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0xa
-		}
+var xxx_messageInfo_ElidedHeader proto.InternalMessageInfo
+
+func (m *ElidedHeader) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *Headers) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *ElidedHeader) GetStyle() int32 {
+	if m != nil {
+		return m.Style
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Headers) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// Segments is a body split at its quoted regions or html blocks, stored as an
+// object of its own so quoted text and html templates are shared between emails
+type Segments struct {
+	Segments []Segment `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments"`
 }
 
-func (m *Headers) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Values) > 0 {
-		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Values[iNdEx])
-			copy(dAtA[i:], m.Values[iNdEx])
-			i = encodeVarintEmail(dAtA, i, uint64(len(m.Values[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
+func (m *Segments) Reset()         { *m = Segments{} }
+func (m *Segments) String() string { return proto.CompactTextString(m) }
+func (*Segments) ProtoMessage()    {}
+func (*Segments) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{19}
 }
-
-func (m *Values) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (m *Segments) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Segments) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
 }
-
-func (m *Values) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *Segments) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Segments.Merge(m, src)
+}
+func (m *Segments) XXX_Size() int {
+	return m.Size()
+}
+func (m *Segments) XXX_DiscardUnknown() {
+	xxx_messageInfo_Segments.DiscardUnknown(m)
 }
 
-func (m *Values) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.V) > 0 {
-		for iNdEx := len(m.V) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.V[iNdEx])
-			copy(dAtA[i:], m.V[iNdEx])
-			i = encodeVarintEmail(dAtA, i, uint64(len(m.V[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-		}
+var xxx_messageInfo_Segments proto.InternalMessageInfo
+
+func (m *Segments) GetSegments() []Segment {
+	if m != nil {
+		return m.Segments
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *Address) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+type Segment struct {
+	// text stored inline, used for the unquoted parts of a body
+	Data string `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// hash of the unixfs object holding quoted text
+	QuotedHash string `protobuf:"bytes,2,opt,name=quotedHash,proto3" json:"quotedHash,omitempty"`
+	// hash of the Segments object holding quoted text which itself contains quotes
+	QuotedSegmentsHash string `protobuf:"bytes,3,opt,name=quotedSegmentsHash,proto3" json:"quotedSegmentsHash,omitempty"`
+	// set when the > quote markers were removed from the quoted text
+	Unquoted bool `protobuf:"varint,4,opt,name=unquoted,proto3" json:"unquoted,omitempty"`
+	// hash of the unixfs object holding an html block, set instead of data
+	DataHash string `protobuf:"bytes,5,opt,name=dataHash,proto3" json:"dataHash,omitempty"`
+}
+
+func (m *Segment) Reset()         { *m = Segment{} }
+func (m *Segment) String() string { return proto.CompactTextString(m) }
+func (*Segment) ProtoMessage()    {}
+func (*Segment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{20}
+}
+func (m *Segment) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Segment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
+}
+func (m *Segment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Segment.Merge(m, src)
+}
+func (m *Segment) XXX_Size() int {
+	return m.Size()
+}
+func (m *Segment) XXX_DiscardUnknown() {
+	xxx_messageInfo_Segment.DiscardUnknown(m)
 }
 
-func (m *Address) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var xxx_messageInfo_Segment proto.InternalMessageInfo
+
+func (m *Segment) GetData() string {
+	if m != nil {
+		return m.Data
+	}
+	return ""
 }
 
-func (m *Address) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Address) > 0 {
-		i -= len(m.Address)
-		copy(dAtA[i:], m.Address)
-		i = encodeVarintEmail(dAtA, i, uint64(len(m.Address)))
-		i--
-		dAtA[i] = 0x12
+func (m *Segment) GetQuotedHash() string {
+	if m != nil {
+		return m.QuotedHash
 	}
-	if len(m.Name) > 0 {
-		i -= len(m.Name)
-		copy(dAtA[i:], m.Name)
-		i = encodeVarintEmail(dAtA, i, uint64(len(m.Name)))
-		i--
-		dAtA[i] = 0xa
+	return ""
+}
+
+func (m *Segment) GetQuotedSegmentsHash() string {
+	if m != nil {
+		return m.QuotedSegmentsHash
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func encodeVarintEmail(dAtA []byte, offset int, v uint64) int {
-	offset -= sovEmail(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *Segment) GetUnquoted() bool {
+	if m != nil {
+		return m.Unquoted
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return false
 }
-func (m *ChunkedEmail) Size() (n int) {
-	if m == nil {
-		return 0
+
+func (m *Segment) GetDataHash() string {
+	if m != nil {
+		return m.DataHash
 	}
-	var l int
-	_ = l
-	if len(m.Parts) > 0 {
-		for k, v := range m.Parts {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + sovEmail(uint64(k)) + 1 + len(v) + sovEmail(uint64(len(v)))
-			n += mapEntrySize + 1 + sovEmail(uint64(mapEntrySize))
-		}
+	return ""
+}
+
+type Attachment struct {
+	FileName    string `protobuf:"bytes,1,opt,name=fileName,proto3" json:"fileName,omitempty"`
+	ContentType string `protobuf:"bytes,2,opt,name=contentType,proto3" json:"contentType,omitempty"`
+	// hash of the unixfs object for the file
+	DataHash string `protobuf:"bytes,3,opt,name=dataHash,proto3" json:"dataHash,omitempty"`
+	// hash of the email object for attached emails, set instead of dataHash
+	EmailHash string       `protobuf:"bytes,4,opt,name=emailHash,proto3" json:"emailHash,omitempty"`
+	Metadata  FileMetadata `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata"`
+}
+
+func (m *Attachment) Reset()         { *m = Attachment{} }
+func (m *Attachment) String() string { return proto.CompactTextString(m) }
+func (*Attachment) ProtoMessage()    {}
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{21}
+}
+func (m *Attachment) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Attachment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return b[:n], nil
+}
+func (m *Attachment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Attachment.Merge(m, src)
+}
+func (m *Attachment) XXX_Size() int {
+	return m.Size()
+}
+func (m *Attachment) XXX_DiscardUnknown() {
+	xxx_messageInfo_Attachment.DiscardUnknown(m)
 }
 
-func (m *Email) Size() (n int) {
-	if m == nil {
-		return 0
+var xxx_messageInfo_Attachment proto.InternalMessageInfo
+
+func (m *Attachment) GetFileName() string {
+	if m != nil {
+		return m.FileName
 	}
-	var l int
-	_ = l
-	l = m.Headers.Size()
-	n += 1 + l + sovEmail(uint64(l))
-	l = len(m.Subject)
-	if l > 0 {
-		n += 1 + l + sovEmail(uint64(l))
+	return ""
+}
+
+func (m *Attachment) GetContentType() string {
+	if m != nil {
+		return m.ContentType
 	}
-	l = m.Addresses.Size()
-	n += 1 + l + sovEmail(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Date)
-	n += 1 + l + sovEmail(uint64(l))
-	l = len(m.MessageID)
-	if l > 0 {
-		n += 1 + l + sovEmail(uint64(l))
+	return ""
+}
+
+func (m *Attachment) GetDataHash() string {
+	if m != nil {
+		return m.DataHash
 	}
-	if len(m.InReplyTo) > 0 {
-		for _, s := range m.InReplyTo {
-			l = len(s)
-			n += 1 + l + sovEmail(uint64(l))
-		}
+	return ""
+}
+
+func (m *Attachment) GetEmailHash() string {
+	if m != nil {
+		return m.EmailHash
 	}
-	if len(m.References) > 0 {
-		for _, s := range m.References {
-			l = len(s)
-			n += 1 + l + sovEmail(uint64(l))
-		}
+	return ""
+}
+
+func (m *Attachment) GetMetadata() FileMetadata {
+	if m != nil {
+		return m.Metadata
 	}
-	if m.Resent != nil {
-		l = m.Resent.Size()
-		n += 1 + l + sovEmail(uint64(l))
+	return FileMetadata{}
+}
+
+type EmbeddedFile struct {
+	ContentId   string `protobuf:"bytes,1,opt,name=contentId,proto3" json:"contentId,omitempty"`
+	ContentType string `protobuf:"bytes,2,opt,name=contentType,proto3" json:"contentType,omitempty"`
+	// hash of the unixfs object for the file
+	DataHash string       `protobuf:"bytes,3,opt,name=dataHash,proto3" json:"dataHash,omitempty"`
+	FileName string       `protobuf:"bytes,4,opt,name=fileName,proto3" json:"fileName,omitempty"`
+	Metadata FileMetadata `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata"`
+	// set for files lifted out of data uris in the html body, which
+	// are referenced from the body as cid:contentId
+	DataUri bool `protobuf:"varint,6,opt,name=dataUri,proto3" json:"dataUri,omitempty"`
+}
+
+func (m *EmbeddedFile) Reset()         { *m = EmbeddedFile{} }
+func (m *EmbeddedFile) String() string { return proto.CompactTextString(m) }
+func (*EmbeddedFile) ProtoMessage()    {}
+func (*EmbeddedFile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{22}
+}
+func (m *EmbeddedFile) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *EmbeddedFile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	l = len(m.HtmlBody)
-	if l > 0 {
-		n += 1 + l + sovEmail(uint64(l))
+	return b[:n], nil
+}
+func (m *EmbeddedFile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EmbeddedFile.Merge(m, src)
+}
+func (m *EmbeddedFile) XXX_Size() int {
+	return m.Size()
+}
+func (m *EmbeddedFile) XXX_DiscardUnknown() {
+	xxx_messageInfo_EmbeddedFile.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EmbeddedFile proto.InternalMessageInfo
+
+func (m *EmbeddedFile) GetContentId() string {
+	if m != nil {
+		return m.ContentId
 	}
-	l = len(m.TextBody)
-	if l > 0 {
-		n += 1 + l + sovEmail(uint64(l))
+	return ""
+}
+
+func (m *EmbeddedFile) GetContentType() string {
+	if m != nil {
+		return m.ContentType
 	}
-	if len(m.Attachments) > 0 {
-		for _, e := range m.Attachments {
-			l = e.Size()
-			n += 1 + l + sovEmail(uint64(l))
-		}
+	return ""
+}
+
+func (m *EmbeddedFile) GetDataHash() string {
+	if m != nil {
+		return m.DataHash
 	}
-	if len(m.EmbeddedFiles) > 0 {
-		for _, e := range m.EmbeddedFiles {
-			l = e.Size()
-			n += 1 + l + sovEmail(uint64(l))
-		}
+	return ""
+}
+
+func (m *EmbeddedFile) GetFileName() string {
+	if m != nil {
+		return m.FileName
 	}
-	return n
+	return ""
 }
 
-func (m *Attachment) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *EmbeddedFile) GetMetadata() FileMetadata {
+	if m != nil {
+		return m.Metadata
 	}
-	var l int
-	_ = l
-	l = len(m.FileName)
-	if l > 0 {
-		n += 1 + l + sovEmail(uint64(l))
+	return FileMetadata{}
+}
+
+func (m *EmbeddedFile) GetDataUri() bool {
+	if m != nil {
+		return m.DataUri
 	}
-	l = len(m.ContentType)
-	if l > 0 {
-		n += 1 + l + sovEmail(uint64(l))
+	return false
+}
+
+// FileMetadata describes an attachment or embedded file without needing to fetch it
+type FileMetadata struct {
+	// size of the decoded file in bytes
+	FileSize uint64 `protobuf:"varint,1,opt,name=fileSize,proto3" json:"fileSize,omitempty"`
+	// hex encoded sha256 digest of the decoded file
+	Sha256 string `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// content-disposition type such as attachment or inline
+	Disposition string `protobuf:"bytes,3,opt,name=disposition,proto3" json:"disposition,omitempty"`
+	Inline      bool   `protobuf:"varint,4,opt,name=inline,proto3" json:"inline,omitempty"`
+	// content-transfer-encoding the file was sent with
+	TransferEncoding string     `protobuf:"bytes,5,opt,name=transferEncoding,proto3" json:"transferEncoding,omitempty"`
+	CreationDate     *time.Time `protobuf:"bytes,6,opt,name=creationDate,proto3,stdtime" json:"creationDate,omitempty"`
+	ModificationDate *time.Time `protobuf:"bytes,7,opt,name=modificationDate,proto3,stdtime" json:"modificationDate,omitempty"`
+	// headers of the mime part containing the file
+	Headers Header `protobuf:"bytes,8,opt,name=headers,proto3" json:"headers"`
+}
+
+func (m *FileMetadata) Reset()         { *m = FileMetadata{} }
+func (m *FileMetadata) String() string { return proto.CompactTextString(m) }
+func (*FileMetadata) ProtoMessage()    {}
+func (*FileMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{23}
+}
+func (m *FileMetadata) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FileMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	l = len(m.DataHash)
-	if l > 0 {
-		n += 1 + l + sovEmail(uint64(l))
+	return b[:n], nil
+}
+func (m *FileMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileMetadata.Merge(m, src)
+}
+func (m *FileMetadata) XXX_Size() int {
+	return m.Size()
+}
+func (m *FileMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FileMetadata proto.InternalMessageInfo
+
+func (m *FileMetadata) GetFileSize() uint64 {
+	if m != nil {
+		return m.FileSize
 	}
-	return n
+	return 0
 }
 
-func (m *EmbeddedFile) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *FileMetadata) GetSha256() string {
+	if m != nil {
+		return m.Sha256
 	}
-	var l int
-	_ = l
-	l = len(m.ContentId)
-	if l > 0 {
-		n += 1 + l + sovEmail(uint64(l))
+	return ""
+}
+
+func (m *FileMetadata) GetDisposition() string {
+	if m != nil {
+		return m.Disposition
 	}
-	l = len(m.ContentType)
-	if l > 0 {
-		n += 1 + l + sovEmail(uint64(l))
+	return ""
+}
+
+func (m *FileMetadata) GetInline() bool {
+	if m != nil {
+		return m.Inline
 	}
-	l = len(m.DataHash)
-	if l > 0 {
-		n += 1 + l + sovEmail(uint64(l))
+	return false
+}
+
+func (m *FileMetadata) GetTransferEncoding() string {
+	if m != nil {
+		return m.TransferEncoding
 	}
-	return n
+	return ""
 }
 
-func (m *Addresses) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *FileMetadata) GetCreationDate() *time.Time {
+	if m != nil {
+		return m.CreationDate
 	}
-	var l int
-	_ = l
-	if m.Sender != nil {
-		l = m.Sender.Size()
-		n += 1 + l + sovEmail(uint64(l))
+	return nil
+}
+
+func (m *FileMetadata) GetModificationDate() *time.Time {
+	if m != nil {
+		return m.ModificationDate
 	}
-	if len(m.From) > 0 {
-		for _, e := range m.From {
-			l = e.Size()
-			n += 1 + l + sovEmail(uint64(l))
-		}
+	return nil
+}
+
+func (m *FileMetadata) GetHeaders() Header {
+	if m != nil {
+		return m.Headers
 	}
-	if len(m.ReplyTo) > 0 {
-		for _, e := range m.ReplyTo {
-			l = e.Size()
-			n += 1 + l + sovEmail(uint64(l))
-		}
+	return Header{}
+}
+
+type Addresses struct {
+	Sender  *Address  `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
+	From    []Address `protobuf:"bytes,2,rep,name=from,proto3" json:"from"`
+	ReplyTo []Address `protobuf:"bytes,3,rep,name=replyTo,proto3" json:"replyTo"`
+	To      []Address `protobuf:"bytes,4,rep,name=to,proto3" json:"to"`
+	Cc      []Address `protobuf:"bytes,5,rep,name=cc,proto3" json:"cc"`
+	Bcc     []Address `protobuf:"bytes,6,rep,name=bcc,proto3" json:"bcc"`
+	// groups used in the address lists, referencing members by index
+	ReplyToGroups []Group `protobuf:"bytes,7,rep,name=replyToGroups,proto3" json:"replyToGroups"`
+	ToGroups      []Group `protobuf:"bytes,8,rep,name=toGroups,proto3" json:"toGroups"`
+	CcGroups      []Group `protobuf:"bytes,9,rep,name=ccGroups,proto3" json:"ccGroups"`
+	BccGroups     []Group `protobuf:"bytes,10,rep,name=bccGroups,proto3" json:"bccGroups"`
+}
+
+func (m *Addresses) Reset()         { *m = Addresses{} }
+func (m *Addresses) String() string { return proto.CompactTextString(m) }
+func (*Addresses) ProtoMessage()    {}
+func (*Addresses) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{24}
+}
+func (m *Addresses) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Addresses) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	if len(m.To) > 0 {
-		for _, e := range m.To {
-			l = e.Size()
-			n += 1 + l + sovEmail(uint64(l))
-		}
+	return b[:n], nil
+}
+func (m *Addresses) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Addresses.Merge(m, src)
+}
+func (m *Addresses) XXX_Size() int {
+	return m.Size()
+}
+func (m *Addresses) XXX_DiscardUnknown() {
+	xxx_messageInfo_Addresses.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Addresses proto.InternalMessageInfo
+
+func (m *Addresses) GetSender() *Address {
+	if m != nil {
+		return m.Sender
 	}
-	if len(m.Cc) > 0 {
-		for _, e := range m.Cc {
-			l = e.Size()
-			n += 1 + l + sovEmail(uint64(l))
-		}
+	return nil
+}
+
+func (m *Addresses) GetFrom() []Address {
+	if m != nil {
+		return m.From
 	}
-	if len(m.Bcc) > 0 {
-		for _, e := range m.Bcc {
-			l = e.Size()
-			n += 1 + l + sovEmail(uint64(l))
-		}
+	return nil
+}
+
+func (m *Addresses) GetReplyTo() []Address {
+	if m != nil {
+		return m.ReplyTo
 	}
-	return n
+	return nil
 }
 
-func (m *Resent) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Addresses) GetTo() []Address {
+	if m != nil {
+		return m.To
 	}
-	var l int
-	_ = l
-	l = m.Addresses.Size()
-	n += 1 + l + sovEmail(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.ResentDate)
-	n += 1 + l + sovEmail(uint64(l))
-	l = len(m.ResentMessageId)
-	if l > 0 {
-		n += 1 + l + sovEmail(uint64(l))
+	return nil
+}
+
+func (m *Addresses) GetCc() []Address {
+	if m != nil {
+		return m.Cc
 	}
-	return n
+	return nil
 }
 
-func (m *Header) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Addresses) GetBcc() []Address {
+	if m != nil {
+		return m.Bcc
 	}
-	var l int
-	_ = l
-	if len(m.Values) > 0 {
-		for k, v := range m.Values {
-			_ = k
-			_ = v
-			l = v.Size()
-			mapEntrySize := 1 + len(k) + sovEmail(uint64(len(k))) + 1 + l + sovEmail(uint64(l))
-			n += mapEntrySize + 1 + sovEmail(uint64(mapEntrySize))
-		}
+	return nil
+}
+
+func (m *Addresses) GetReplyToGroups() []Group {
+	if m != nil {
+		return m.ReplyToGroups
 	}
-	return n
+	return nil
 }
 
-func (m *Headers) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Addresses) GetToGroups() []Group {
+	if m != nil {
+		return m.ToGroups
 	}
-	var l int
-	_ = l
-	if len(m.Values) > 0 {
-		for _, s := range m.Values {
-			l = len(s)
-			n += 1 + l + sovEmail(uint64(l))
-		}
+	return nil
+}
+
+func (m *Addresses) GetCcGroups() []Group {
+	if m != nil {
+		return m.CcGroups
 	}
-	return n
+	return nil
 }
 
-func (m *Values) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Addresses) GetBccGroups() []Group {
+	if m != nil {
+		return m.BccGroups
 	}
-	var l int
-	_ = l
-	if len(m.V) > 0 {
-		for _, s := range m.V {
-			l = len(s)
-			n += 1 + l + sovEmail(uint64(l))
+	return nil
+}
+
+// Group is an RFC 5322 group such as "undisclosed-recipients:;"
+type Group struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// indexes of the members in the address list the group belongs to
+	Members []int32 `protobuf:"varint,2,rep,packed,name=members,proto3" json:"members,omitempty"`
+}
+
+func (m *Group) Reset()         { *m = Group{} }
+func (m *Group) String() string { return proto.CompactTextString(m) }
+func (*Group) ProtoMessage()    {}
+func (*Group) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{25}
+}
+func (m *Group) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Group) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *Group) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Group.Merge(m, src)
+}
+func (m *Group) XXX_Size() int {
+	return m.Size()
+}
+func (m *Group) XXX_DiscardUnknown() {
+	xxx_messageInfo_Group.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Group proto.InternalMessageInfo
+
+func (m *Group) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Group) GetMembers() []int32 {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+type Resent struct {
+	Addresses       Addresses `protobuf:"bytes,1,opt,name=addresses,proto3" json:"addresses"`
+	ResentDate      time.Time `protobuf:"bytes,2,opt,name=resentDate,proto3,stdtime" json:"resentDate"`
+	ResentMessageId string    `protobuf:"bytes,3,opt,name=resentMessageId,proto3" json:"resentMessageId,omitempty"`
+	// offset of the original resent date from utc in seconds
+	ResentDateOffset int32 `protobuf:"varint,4,opt,name=resentDateOffset,proto3" json:"resentDateOffset,omitempty"`
+	// the unparsed resent date header
+	RawResentDate string `protobuf:"bytes,5,opt,name=rawResentDate,proto3" json:"rawResentDate,omitempty"`
+	// set when the resent date header is present but could not be parsed
+	ResentDateInvalid bool `protobuf:"varint,6,opt,name=resentDateInvalid,proto3" json:"resentDateInvalid,omitempty"`
+}
+
+func (m *Resent) Reset()         { *m = Resent{} }
+func (m *Resent) String() string { return proto.CompactTextString(m) }
+func (*Resent) ProtoMessage()    {}
+func (*Resent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{26}
+}
+func (m *Resent) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Resent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *Resent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Resent.Merge(m, src)
+}
+func (m *Resent) XXX_Size() int {
+	return m.Size()
+}
+func (m *Resent) XXX_DiscardUnknown() {
+	xxx_messageInfo_Resent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Resent proto.InternalMessageInfo
+
+func (m *Resent) GetAddresses() Addresses {
+	if m != nil {
+		return m.Addresses
+	}
+	return Addresses{}
+}
+
+func (m *Resent) GetResentDate() time.Time {
+	if m != nil {
+		return m.ResentDate
+	}
+	return time.Time{}
+}
+
+func (m *Resent) GetResentMessageId() string {
+	if m != nil {
+		return m.ResentMessageId
+	}
+	return ""
+}
+
+func (m *Resent) GetResentDateOffset() int32 {
+	if m != nil {
+		return m.ResentDateOffset
+	}
+	return 0
+}
+
+func (m *Resent) GetRawResentDate() string {
+	if m != nil {
+		return m.RawResentDate
+	}
+	return ""
+}
+
+func (m *Resent) GetResentDateInvalid() bool {
+	if m != nil {
+		return m.ResentDateInvalid
+	}
+	return false
+}
+
+type Header struct {
+	Values map[string]Headers `protobuf:"bytes,1,rep,name=values,proto3" json:"values" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *Header) Reset()         { *m = Header{} }
+func (m *Header) String() string { return proto.CompactTextString(m) }
+func (*Header) ProtoMessage()    {}
+func (*Header) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{27}
+}
+func (m *Header) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Header) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *Header) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Header.Merge(m, src)
+}
+func (m *Header) XXX_Size() int {
+	return m.Size()
+}
+func (m *Header) XXX_DiscardUnknown() {
+	xxx_messageInfo_Header.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Header proto.InternalMessageInfo
+
+func (m *Header) GetValues() map[string]Headers {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type Headers struct {
+	Values []string `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (m *Headers) Reset()         { *m = Headers{} }
+func (m *Headers) String() string { return proto.CompactTextString(m) }
+func (*Headers) ProtoMessage()    {}
+func (*Headers) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{28}
+}
+func (m *Headers) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Headers) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *Headers) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Headers.Merge(m, src)
+}
+func (m *Headers) XXX_Size() int {
+	return m.Size()
+}
+func (m *Headers) XXX_DiscardUnknown() {
+	xxx_messageInfo_Headers.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Headers proto.InternalMessageInfo
+
+func (m *Headers) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+// Values is basically an embedded slice in an email header
+type Values struct {
+	V []string `protobuf:"bytes,1,rep,name=v,proto3" json:"v,omitempty"`
+}
+
+func (m *Values) Reset()         { *m = Values{} }
+func (m *Values) String() string { return proto.CompactTextString(m) }
+func (*Values) ProtoMessage()    {}
+func (*Values) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{29}
+}
+func (m *Values) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Values) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *Values) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Values.Merge(m, src)
+}
+func (m *Values) XXX_Size() int {
+	return m.Size()
+}
+func (m *Values) XXX_DiscardUnknown() {
+	xxx_messageInfo_Values.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Values proto.InternalMessageInfo
+
+func (m *Values) GetV() []string {
+	if m != nil {
+		return m.V
+	}
+	return nil
+}
+
+type Address struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Address string `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	// normalized form of the address, only set when it differs from address
+	Normalized string `protobuf:"bytes,3,opt,name=normalized,proto3" json:"normalized,omitempty"`
+	// set when the local part contains non-ascii characters
+	Smtputf8 bool `protobuf:"varint,4,opt,name=smtputf8,proto3" json:"smtputf8,omitempty"`
+}
+
+func (m *Address) Reset()         { *m = Address{} }
+func (m *Address) String() string { return proto.CompactTextString(m) }
+func (*Address) ProtoMessage()    {}
+func (*Address) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{30}
+}
+func (m *Address) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Address) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *Address) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Address.Merge(m, src)
+}
+func (m *Address) XXX_Size() int {
+	return m.Size()
+}
+func (m *Address) XXX_DiscardUnknown() {
+	xxx_messageInfo_Address.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Address proto.InternalMessageInfo
+
+func (m *Address) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Address) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Address) GetNormalized() string {
+	if m != nil {
+		return m.Normalized
+	}
+	return ""
+}
+
+func (m *Address) GetSmtputf8() bool {
+	if m != nil {
+		return m.Smtputf8
+	}
+	return false
+}
+
+// SearchIndex is the root of a full text search index over stored emails.
+// terms are kept in a prolly tree of TreeNode objects, so adding emails only
+// rewrites the nodes on the paths to their terms
+type SearchIndex struct {
+	// root of the tree from terms to their serialized SearchPostings, empty
+	// when no emails were indexed
+	Terms string `protobuf:"bytes,1,opt,name=terms,proto3" json:"terms,omitempty"`
+	// schema version the index was stored with
+	Version uint32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (m *SearchIndex) Reset()         { *m = SearchIndex{} }
+func (m *SearchIndex) String() string { return proto.CompactTextString(m) }
+func (*SearchIndex) ProtoMessage()    {}
+func (*SearchIndex) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{31}
+}
+func (m *SearchIndex) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SearchIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *SearchIndex) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchIndex.Merge(m, src)
+}
+func (m *SearchIndex) XXX_Size() int {
+	return m.Size()
+}
+func (m *SearchIndex) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchIndex.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchIndex proto.InternalMessageInfo
+
+func (m *SearchIndex) GetTerms() string {
+	if m != nil {
+		return m.Terms
+	}
+	return ""
+}
+
+func (m *SearchIndex) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// SearchPostings are the postings of a term in a search index. they are kept
+// inline until there are too many, then move to a tree of their own from the
+// hashes of emails to their serialized Posting without its hash, so adding
+// an email to a frequent term doesn't rewrite all of its postings
+type SearchPostings struct {
+	// sorted by hash
+	Postings []Posting `protobuf:"bytes,1,rep,name=postings,proto3" json:"postings"`
+	// root of the tree holding the postings instead
+	Tree string `protobuf:"bytes,2,opt,name=tree,proto3" json:"tree,omitempty"`
+}
+
+func (m *SearchPostings) Reset()         { *m = SearchPostings{} }
+func (m *SearchPostings) String() string { return proto.CompactTextString(m) }
+func (*SearchPostings) ProtoMessage()    {}
+func (*SearchPostings) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{32}
+}
+func (m *SearchPostings) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SearchPostings) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *SearchPostings) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchPostings.Merge(m, src)
+}
+func (m *SearchPostings) XXX_Size() int {
+	return m.Size()
+}
+func (m *SearchPostings) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchPostings.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchPostings proto.InternalMessageInfo
+
+func (m *SearchPostings) GetPostings() []Posting {
+	if m != nil {
+		return m.Postings
+	}
+	return nil
+}
+
+func (m *SearchPostings) GetTree() string {
+	if m != nil {
+		return m.Tree
+	}
+	return ""
+}
+
+// Posting records an email containing a term
+type Posting struct {
+	Hash string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	// bit set of the fields the term was found in
+	Fields uint32 `protobuf:"varint,2,opt,name=fields,proto3" json:"fields,omitempty"`
+	// number of times the term was found
+	Count uint32 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	// number of times the term was found in each field of fields, in bit
+	// order, only set when it was found in more than one field
+	FieldCounts []uint32 `protobuf:"varint,4,rep,packed,name=fieldCounts,proto3" json:"fieldCounts,omitempty"`
+}
+
+func (m *Posting) Reset()         { *m = Posting{} }
+func (m *Posting) String() string { return proto.CompactTextString(m) }
+func (*Posting) ProtoMessage()    {}
+func (*Posting) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{33}
+}
+func (m *Posting) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Posting) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *Posting) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Posting.Merge(m, src)
+}
+func (m *Posting) XXX_Size() int {
+	return m.Size()
+}
+func (m *Posting) XXX_DiscardUnknown() {
+	xxx_messageInfo_Posting.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Posting proto.InternalMessageInfo
+
+func (m *Posting) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Posting) GetFields() uint32 {
+	if m != nil {
+		return m.Fields
+	}
+	return 0
+}
+
+func (m *Posting) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *Posting) GetFieldCounts() []uint32 {
+	if m != nil {
+		return m.FieldCounts
+	}
+	return nil
+}
+
+// MetadataIndex is the root of an index of the metadata of stored emails, so
+// structured queries are answered without fetching the emails. records are
+// kept in a prolly tree of TreeNode objects, so adding emails only rewrites
+// the nodes on the paths to their records
+type MetadataIndex struct {
+	// root of the tree from the hashes of emails to their serialized
+	// MetadataRecord without its hash, empty when no emails were indexed
+	Records string `protobuf:"bytes,1,opt,name=records,proto3" json:"records,omitempty"`
+	// schema version the index was stored with
+	Version uint32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// root of a date index of the indexed emails, so queries with a date
+	// range only read the records of the emails in the range
+	Dates string `protobuf:"bytes,3,opt,name=dates,proto3" json:"dates,omitempty"`
+}
+
+func (m *MetadataIndex) Reset()         { *m = MetadataIndex{} }
+func (m *MetadataIndex) String() string { return proto.CompactTextString(m) }
+func (*MetadataIndex) ProtoMessage()    {}
+func (*MetadataIndex) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{34}
+}
+func (m *MetadataIndex) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MetadataIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *MetadataIndex) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetadataIndex.Merge(m, src)
+}
+func (m *MetadataIndex) XXX_Size() int {
+	return m.Size()
+}
+func (m *MetadataIndex) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetadataIndex.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetadataIndex proto.InternalMessageInfo
+
+func (m *MetadataIndex) GetRecords() string {
+	if m != nil {
+		return m.Records
+	}
+	return ""
+}
+
+func (m *MetadataIndex) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *MetadataIndex) GetDates() string {
+	if m != nil {
+		return m.Dates
+	}
+	return ""
+}
+
+// MetadataRecord is the metadata of a stored email
+type MetadataRecord struct {
+	Hash string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	// lower case domains of the sender, from and reply-to addresses
+	FromDomains []string `protobuf:"bytes,2,rep,name=fromDomains,proto3" json:"fromDomains,omitempty"`
+	// lower case domains of the to addresses
+	ToDomains []string `protobuf:"bytes,3,rep,name=toDomains,proto3" json:"toDomains,omitempty"`
+	// lower case domains of the cc and bcc addresses
+	CcDomains []string  `protobuf:"bytes,4,rep,name=ccDomains,proto3" json:"ccDomains,omitempty"`
+	Date      time.Time `protobuf:"bytes,5,opt,name=date,proto3,stdtime" json:"date"`
+	// size of the bodies, attachments and embedded files in bytes
+	MessageSize uint64 `protobuf:"varint,6,opt,name=messageSize,proto3" json:"messageSize,omitempty"`
+	// number of attachments
+	Attachments uint32 `protobuf:"varint,7,opt,name=attachments,proto3" json:"attachments,omitempty"`
+	// lower case content types of the attachments and embedded files
+	AttachmentTypes []string `protobuf:"bytes,8,rep,name=attachmentTypes,proto3" json:"attachmentTypes,omitempty"`
+	// names of the headers present
+	Headers []string `protobuf:"bytes,9,rep,name=headers,proto3" json:"headers,omitempty"`
+}
+
+func (m *MetadataRecord) Reset()         { *m = MetadataRecord{} }
+func (m *MetadataRecord) String() string { return proto.CompactTextString(m) }
+func (*MetadataRecord) ProtoMessage()    {}
+func (*MetadataRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{35}
+}
+func (m *MetadataRecord) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MetadataRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *MetadataRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetadataRecord.Merge(m, src)
+}
+func (m *MetadataRecord) XXX_Size() int {
+	return m.Size()
+}
+func (m *MetadataRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetadataRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetadataRecord proto.InternalMessageInfo
+
+func (m *MetadataRecord) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *MetadataRecord) GetFromDomains() []string {
+	if m != nil {
+		return m.FromDomains
+	}
+	return nil
+}
+
+func (m *MetadataRecord) GetToDomains() []string {
+	if m != nil {
+		return m.ToDomains
+	}
+	return nil
+}
+
+func (m *MetadataRecord) GetCcDomains() []string {
+	if m != nil {
+		return m.CcDomains
+	}
+	return nil
+}
+
+func (m *MetadataRecord) GetDate() time.Time {
+	if m != nil {
+		return m.Date
+	}
+	return time.Time{}
+}
+
+func (m *MetadataRecord) GetMessageSize() uint64 {
+	if m != nil {
+		return m.MessageSize
+	}
+	return 0
+}
+
+func (m *MetadataRecord) GetAttachments() uint32 {
+	if m != nil {
+		return m.Attachments
+	}
+	return 0
+}
+
+func (m *MetadataRecord) GetAttachmentTypes() []string {
+	if m != nil {
+		return m.AttachmentTypes
+	}
+	return nil
+}
+
+func (m *MetadataRecord) GetHeaders() []string {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
+// DateEntry is an email in the date index, a tree of TreeNode objects keyed
+// by the date, message id and hash of the emails
+type DateEntry struct {
+	Date      time.Time `protobuf:"bytes,1,opt,name=date,proto3,stdtime" json:"date"`
+	MessageID string    `protobuf:"bytes,2,opt,name=messageID,proto3" json:"messageID,omitempty"`
+	Hash      string    `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (m *DateEntry) Reset()         { *m = DateEntry{} }
+func (m *DateEntry) String() string { return proto.CompactTextString(m) }
+func (*DateEntry) ProtoMessage()    {}
+func (*DateEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{36}
+}
+func (m *DateEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DateEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *DateEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DateEntry.Merge(m, src)
+}
+func (m *DateEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *DateEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_DateEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DateEntry proto.InternalMessageInfo
+
+func (m *DateEntry) GetDate() time.Time {
+	if m != nil {
+		return m.Date
+	}
+	return time.Time{}
+}
+
+func (m *DateEntry) GetMessageID() string {
+	if m != nil {
+		return m.MessageID
+	}
+	return ""
+}
+
+func (m *DateEntry) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+// TreeNode is a node of a prolly tree from keys to values, with nodes ending
+// after the keys whose digest falls under a threshold, so the same entries
+// give the same tree whatever order they were added in, adding entries only
+// rewrites the nodes they fall in, and updating a value never moves a boundary
+type TreeNode struct {
+	// 0 for leaves
+	Level uint32 `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
+	// set on leaves, sorted by key
+	Entries []TreeEntry `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries"`
+	// set on internal nodes, in the order of their keys
+	Children []TreeChild `protobuf:"bytes,3,rep,name=children,proto3" json:"children"`
+	// schema version the node was stored with
+	Version uint32 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (m *TreeNode) Reset()         { *m = TreeNode{} }
+func (m *TreeNode) String() string { return proto.CompactTextString(m) }
+func (*TreeNode) ProtoMessage()    {}
+func (*TreeNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{37}
+}
+func (m *TreeNode) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TreeNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *TreeNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TreeNode.Merge(m, src)
+}
+func (m *TreeNode) XXX_Size() int {
+	return m.Size()
+}
+func (m *TreeNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_TreeNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TreeNode proto.InternalMessageInfo
+
+func (m *TreeNode) GetLevel() uint32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *TreeNode) GetEntries() []TreeEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func (m *TreeNode) GetChildren() []TreeChild {
+	if m != nil {
+		return m.Children
+	}
+	return nil
+}
+
+func (m *TreeNode) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type TreeEntry struct {
+	Key   []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *TreeEntry) Reset()         { *m = TreeEntry{} }
+func (m *TreeEntry) String() string { return proto.CompactTextString(m) }
+func (*TreeEntry) ProtoMessage()    {}
+func (*TreeEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{38}
+}
+func (m *TreeEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TreeEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *TreeEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TreeEntry.Merge(m, src)
+}
+func (m *TreeEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *TreeEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_TreeEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TreeEntry proto.InternalMessageInfo
+
+func (m *TreeEntry) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *TreeEntry) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+type TreeChild struct {
+	Hash string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	// last key under the child
+	Last []byte `protobuf:"bytes,2,opt,name=last,proto3" json:"last,omitempty"`
+	// number of entries under the child
+	Count uint64 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (m *TreeChild) Reset()         { *m = TreeChild{} }
+func (m *TreeChild) String() string { return proto.CompactTextString(m) }
+func (*TreeChild) ProtoMessage()    {}
+func (*TreeChild) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6175298cb4ed6faa, []int{39}
+}
+func (m *TreeChild) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TreeChild) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *TreeChild) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TreeChild.Merge(m, src)
+}
+func (m *TreeChild) XXX_Size() int {
+	return m.Size()
+}
+func (m *TreeChild) XXX_DiscardUnknown() {
+	xxx_messageInfo_TreeChild.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TreeChild proto.InternalMessageInfo
+
+func (m *TreeChild) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *TreeChild) GetLast() []byte {
+	if m != nil {
+		return m.Last
+	}
+	return nil
+}
+
+func (m *TreeChild) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ChunkedEmail)(nil), "pb.ChunkedEmail")
+	proto.RegisterMapType((map[int32]string)(nil), "pb.ChunkedEmail.PartsEntry")
+	proto.RegisterType((*Email)(nil), "pb.Email")
+	proto.RegisterType((*Summary)(nil), "pb.Summary")
+	proto.RegisterType((*EmailDelta)(nil), "pb.EmailDelta")
+	proto.RegisterType((*DeltaOp)(nil), "pb.DeltaOp")
+	proto.RegisterType((*HeaderTemplate)(nil), "pb.HeaderTemplate")
+	proto.RegisterType((*TemplateHeader)(nil), "pb.TemplateHeader")
+	proto.RegisterType((*ArchiveNode)(nil), "pb.ArchiveNode")
+	proto.RegisterType((*ArchiveSlot)(nil), "pb.ArchiveSlot")
+	proto.RegisterType((*ArchiveLink)(nil), "pb.ArchiveLink")
+	proto.RegisterType((*Folder)(nil), "pb.Folder")
+	proto.RegisterType((*FolderEntry)(nil), "pb.FolderEntry")
+	proto.RegisterType((*FolderLink)(nil), "pb.FolderLink")
+	proto.RegisterType((*Thread)(nil), "pb.Thread")
+	proto.RegisterType((*ThreadNode)(nil), "pb.ThreadNode")
+	proto.RegisterType((*MessageIndex)(nil), "pb.MessageIndex")
+	proto.RegisterType((*MessageIndexEntry)(nil), "pb.MessageIndexEntry")
+	proto.RegisterType((*IndexedCopy)(nil), "pb.IndexedCopy")
+	proto.RegisterType((*ElidedHeader)(nil), "pb.ElidedHeader")
+	proto.RegisterType((*Segments)(nil), "pb.Segments")
+	proto.RegisterType((*Segment)(nil), "pb.Segment")
+	proto.RegisterType((*Attachment)(nil), "pb.Attachment")
+	proto.RegisterType((*EmbeddedFile)(nil), "pb.EmbeddedFile")
+	proto.RegisterType((*FileMetadata)(nil), "pb.FileMetadata")
+	proto.RegisterType((*Addresses)(nil), "pb.Addresses")
+	proto.RegisterType((*Group)(nil), "pb.Group")
+	proto.RegisterType((*Resent)(nil), "pb.Resent")
+	proto.RegisterType((*Header)(nil), "pb.Header")
+	proto.RegisterMapType((map[string]Headers)(nil), "pb.Header.ValuesEntry")
+	proto.RegisterType((*Headers)(nil), "pb.Headers")
+	proto.RegisterType((*Values)(nil), "pb.Values")
+	proto.RegisterType((*Address)(nil), "pb.Address")
+	proto.RegisterType((*SearchIndex)(nil), "pb.SearchIndex")
+	proto.RegisterType((*SearchPostings)(nil), "pb.SearchPostings")
+	proto.RegisterType((*Posting)(nil), "pb.Posting")
+	proto.RegisterType((*MetadataIndex)(nil), "pb.MetadataIndex")
+	proto.RegisterType((*MetadataRecord)(nil), "pb.MetadataRecord")
+	proto.RegisterType((*DateEntry)(nil), "pb.DateEntry")
+	proto.RegisterType((*TreeNode)(nil), "pb.TreeNode")
+	proto.RegisterType((*TreeEntry)(nil), "pb.TreeEntry")
+	proto.RegisterType((*TreeChild)(nil), "pb.TreeChild")
+}
+
+func init() { proto.RegisterFile("email.proto", fileDescriptor_6175298cb4ed6faa) }
+
+var fileDescriptor_6175298cb4ed6faa = []byte{
+	// 2268 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x59, 0xcd, 0x8f, 0x1c, 0x57,
+	0x11, 0x77, 0xcf, 0x77, 0xd7, 0xcc, 0xac, 0x9d, 0x87, 0xb3, 0x34, 0x1b, 0xb3, 0x1e, 0x77, 0x82,
+	0xb4, 0x8a, 0xf1, 0x98, 0x6c, 0xec, 0xc8, 0xa0, 0x20, 0x11, 0x7b, 0x6d, 0x6c, 0x29, 0x8e, 0xad,
+	0xde, 0x05, 0x89, 0x13, 0xf4, 0x76, 0xbf, 0x99, 0xe9, 0xb8, 0xbf, 0xe8, 0x7e, 0xb3, 0xce, 0x46,
+	0xdc, 0x90, 0x38, 0xa2, 0x5c, 0x10, 0x12, 0x17, 0x4e, 0x9c, 0x38, 0x70, 0xe0, 0xc2, 0x1f, 0xc0,
+	0x21, 0xc7, 0x1c, 0x38, 0xe4, 0x04, 0xc8, 0xbe, 0xf0, 0x67, 0xa0, 0xaa, 0xf7, 0x5e, 0xf7, 0xeb,
+	0x9d, 0x19, 0xdb, 0xe0, 0xd3, 0x74, 0x55, 0xfd, 0xea, 0x7d, 0x54, 0xd5, 0xab, 0xaa, 0xf7, 0x06,
+	0x86, 0x3c, 0xf1, 0xa3, 0x78, 0x9a, 0x17, 0x99, 0xc8, 0x58, 0x2b, 0x3f, 0xde, 0xb9, 0x36, 0x8f,
+	0xc4, 0x62, 0x79, 0x3c, 0x0d, 0xb2, 0xe4, 0xfa, 0x3c, 0x9b, 0x67, 0xd7, 0x49, 0x74, 0xbc, 0x9c,
+	0x11, 0x45, 0x04, 0x7d, 0x49, 0x95, 0x9d, 0xcb, 0xf3, 0x2c, 0x9b, 0xc7, 0xbc, 0x46, 0x89, 0x28,
+	0xe1, 0xa5, 0xf0, 0x93, 0x5c, 0x02, 0xdc, 0xdf, 0x59, 0x30, 0xba, 0xb3, 0x58, 0xa6, 0x4f, 0x78,
+	0x78, 0x17, 0xa7, 0x62, 0xef, 0x41, 0x37, 0xf7, 0x0b, 0x51, 0x3a, 0xd6, 0xa4, 0xbd, 0x37, 0xdc,
+	0x7f, 0x6b, 0x9a, 0x1f, 0x4f, 0x4d, 0xc0, 0xf4, 0x31, 0x4a, 0xef, 0xa6, 0xa2, 0x38, 0xf5, 0x24,
+	0x92, 0x39, 0xd0, 0x3f, 0xe1, 0x45, 0x19, 0x65, 0xa9, 0xd3, 0x9a, 0x58, 0x7b, 0x63, 0x4f, 0x93,
+	0x3b, 0xb7, 0x00, 0x6a, 0x38, 0xbb, 0x00, 0xed, 0x27, 0xfc, 0xd4, 0xb1, 0x26, 0xd6, 0x5e, 0xd7,
+	0xc3, 0x4f, 0x76, 0x11, 0xba, 0x27, 0x7e, 0xbc, 0xe4, 0xa4, 0x67, 0x7b, 0x92, 0xf8, 0x41, 0xeb,
+	0x96, 0xe5, 0xfe, 0xd6, 0x86, 0xae, 0x5c, 0xd0, 0xbb, 0xd0, 0x5f, 0x70, 0x3f, 0xe4, 0x45, 0x49,
+	0x9a, 0xc3, 0x7d, 0xc0, 0x25, 0xdd, 0x27, 0xd6, 0xed, 0xce, 0x97, 0xff, 0xbc, 0x7c, 0xce, 0xd3,
+	0x00, 0x5c, 0x49, 0xb9, 0x3c, 0xfe, 0x94, 0x07, 0x42, 0x8d, 0xa8, 0x49, 0xf6, 0x1e, 0xd8, 0x7e,
+	0x18, 0x16, 0xbc, 0x2c, 0x79, 0xe9, 0xb4, 0x69, 0x9c, 0x31, 0x8e, 0xf3, 0x91, 0x66, 0xaa, 0xa1,
+	0x6a, 0x14, 0xbb, 0x05, 0x9d, 0xd0, 0x17, 0xdc, 0xe9, 0x10, 0x7a, 0x67, 0x2a, 0x4d, 0x39, 0xd5,
+	0xa6, 0x9c, 0x1e, 0x69, 0x53, 0xde, 0x1e, 0xa0, 0xea, 0x17, 0xff, 0xba, 0x6c, 0x79, 0xa4, 0xc1,
+	0x2e, 0x81, 0x9d, 0xf0, 0xb2, 0xf4, 0xe7, 0xfc, 0xc1, 0x81, 0xd3, 0xa5, 0x85, 0xd4, 0x0c, 0x94,
+	0x46, 0xa9, 0xc7, 0xf3, 0xf8, 0xf4, 0x28, 0x73, 0x7a, 0x93, 0x36, 0x4a, 0x2b, 0x06, 0xdb, 0x05,
+	0x28, 0xf8, 0x8c, 0x17, 0x3c, 0x0d, 0x78, 0xe9, 0xf4, 0x49, 0x6c, 0x70, 0x98, 0x0b, 0xbd, 0x82,
+	0x97, 0x3c, 0x15, 0xce, 0xa0, 0xb6, 0x86, 0x47, 0x1c, 0x4f, 0x49, 0xd8, 0x0e, 0x0c, 0x16, 0x22,
+	0x89, 0x6f, 0x67, 0xe1, 0xa9, 0x03, 0x34, 0x7d, 0x45, 0xa3, 0x4c, 0xf0, 0xcf, 0x04, 0xc9, 0x86,
+	0x52, 0xa6, 0x69, 0xf6, 0x01, 0x0c, 0x7d, 0x21, 0xfc, 0x60, 0x91, 0xf0, 0x54, 0x94, 0xce, 0x88,
+	0x22, 0x60, 0x8b, 0xcc, 0x54, 0xb1, 0x95, 0x9d, 0x4c, 0x20, 0xfb, 0x10, 0xc6, 0x3c, 0x39, 0xe6,
+	0x61, 0xc8, 0xc3, 0x7b, 0x51, 0xcc, 0x4b, 0x67, 0x4c, 0x9a, 0x17, 0x50, 0xf3, 0xae, 0x21, 0x50,
+	0xba, 0x4d, 0x30, 0x9b, 0xc0, 0x10, 0x57, 0x70, 0x67, 0xe1, 0x17, 0x25, 0x17, 0xce, 0x16, 0x2d,
+	0xca, 0x64, 0x21, 0x02, 0xd7, 0xaf, 0x11, 0xe7, 0x25, 0xc2, 0x60, 0xe9, 0x31, 0x70, 0x17, 0x9e,
+	0xff, 0xd4, 0xb9, 0x30, 0xb1, 0xf6, 0x46, 0x9e, 0xc9, 0xd2, 0x63, 0x68, 0xc4, 0x1b, 0x12, 0x61,
+	0xb0, 0xd0, 0xf2, 0xe8, 0xbd, 0x47, 0xb3, 0x19, 0x4e, 0xc2, 0x28, 0x4a, 0x0d, 0x0e, 0x06, 0x57,
+	0xe1, 0x3f, 0x3d, 0xc0, 0x90, 0xf8, 0x86, 0x0c, 0x2e, 0x45, 0xe2, 0xd8, 0x88, 0x7b, 0x90, 0x9e,
+	0xf8, 0x71, 0x14, 0x3a, 0x17, 0x27, 0xd6, 0xde, 0xc0, 0x33, 0x59, 0xec, 0x06, 0x8c, 0xa4, 0x6f,
+	0x6e, 0xc7, 0x59, 0xf0, 0xa4, 0x74, 0xde, 0x9c, 0xb4, 0x9b, 0xbe, 0x53, 0xa6, 0x69, 0xa0, 0x98,
+	0x0b, 0x23, 0xbd, 0x85, 0xfb, 0x7e, 0xb9, 0x70, 0xb6, 0x69, 0xda, 0x06, 0x0f, 0x31, 0x7a, 0x13,
+	0x84, 0xf9, 0xa6, 0xc4, 0x98, 0x3c, 0xf6, 0x2e, 0x5c, 0x40, 0x9d, 0x43, 0x3e, 0x27, 0x7f, 0x11,
+	0xce, 0x21, 0xdc, 0x0a, 0x1f, 0xb1, 0xa8, 0xdb, 0xc0, 0x7e, 0x4b, 0x62, 0xcf, 0xf2, 0xc9, 0xef,
+	0x71, 0x14, 0xf2, 0xf0, 0xbe, 0x3a, 0xa0, 0x3b, 0x86, 0xdf, 0x0d, 0x41, 0xe5, 0x77, 0x13, 0xcc,
+	0xa6, 0xc0, 0xe4, 0xb9, 0x3d, 0xe2, 0x49, 0x1e, 0xfb, 0x82, 0xd3, 0x5c, 0x6f, 0xd1, 0x5c, 0x6b,
+	0x24, 0x66, 0x9a, 0xb9, 0xd4, 0x48, 0x33, 0x18, 0xd3, 0x41, 0x56, 0x48, 0xfd, 0x6f, 0xcb, 0x98,
+	0xd6, 0x34, 0x7b, 0x07, 0xba, 0x21, 0x8f, 0x85, 0xef, 0xec, 0x4e, 0x2c, 0x1d, 0xcd, 0x94, 0x58,
+	0x0e, 0x90, 0xeb, 0x49, 0x21, 0x7a, 0xb0, 0x5c, 0x26, 0x89, 0x5f, 0x48, 0x23, 0x5e, 0x96, 0x11,
+	0x66, 0xb0, 0xdc, 0x3f, 0xb6, 0xa0, 0x7f, 0x28, 0x69, 0x33, 0xcd, 0x58, 0xcd, 0x34, 0xf3, 0x1d,
+	0xe8, 0xcc, 0x8a, 0x2c, 0x71, 0x5a, 0x64, 0x88, 0xa1, 0x91, 0x61, 0x94, 0x0d, 0x48, 0xcc, 0xae,
+	0x40, 0x4b, 0x64, 0x4e, 0x7b, 0x13, 0xa8, 0x25, 0xb2, 0xd7, 0xc8, 0x3e, 0x13, 0x18, 0xaa, 0x64,
+	0x73, 0x18, 0x7d, 0xce, 0x29, 0xff, 0x74, 0x3c, 0x93, 0x85, 0x08, 0xf3, 0x9c, 0xf7, 0xc8, 0x9a,
+	0x26, 0x8b, 0x76, 0x98, 0x46, 0x79, 0xce, 0x85, 0xd3, 0x57, 0x3b, 0x94, 0xa4, 0xe9, 0x85, 0x41,
+	0xc3, 0x0b, 0xee, 0xaf, 0x2d, 0x80, 0xda, 0xb2, 0xe8, 0x94, 0x63, 0xbf, 0x94, 0x4e, 0x91, 0x56,
+	0xaa, 0x68, 0x3c, 0x6a, 0xf8, 0x7d, 0x10, 0xcd, 0x79, 0x29, 0x53, 0xf5, 0xc8, 0x33, 0x38, 0x58,
+	0x17, 0x42, 0x9e, 0x8b, 0x05, 0x65, 0xea, 0xb1, 0x27, 0x09, 0xf6, 0x36, 0xb4, 0xb3, 0xbc, 0x74,
+	0x3a, 0xb5, 0xd9, 0x68, 0xa6, 0x47, 0xb9, 0x32, 0x1b, 0x4a, 0xdd, 0x87, 0xd0, 0x57, 0x5c, 0xb6,
+	0x0d, 0xbd, 0x4c, 0x1e, 0x66, 0x8b, 0x6c, 0xa0, 0x28, 0xe4, 0xc7, 0x3c, 0x9d, 0x8b, 0x05, 0xcd,
+	0xdc, 0xf1, 0x14, 0xc5, 0x18, 0x99, 0xdc, 0xa7, 0x49, 0x47, 0x64, 0x4c, 0xdf, 0x3d, 0x80, 0xad,
+	0xfb, 0x8d, 0x50, 0x64, 0xfb, 0x66, 0x3d, 0xc2, 0x95, 0x30, 0x5c, 0x49, 0x15, 0xa9, 0xeb, 0xea,
+	0x92, 0xfb, 0x21, 0x6c, 0x35, 0x01, 0x38, 0x57, 0xea, 0x27, 0x5c, 0x59, 0x86, 0xbe, 0x71, 0x5d,
+	0x54, 0x00, 0x4b, 0x0a, 0x1f, 0xdb, 0x53, 0x94, 0xfb, 0x29, 0x0c, 0x3f, 0x2a, 0x82, 0x45, 0x74,
+	0xc2, 0x3f, 0xc9, 0x42, 0xce, 0xae, 0x42, 0xb7, 0x8c, 0xb3, 0xaa, 0x42, 0x9f, 0xa7, 0xf8, 0x91,
+	0xf2, 0xc3, 0x38, 0xd3, 0x99, 0x44, 0x62, 0xd0, 0x92, 0x41, 0xb6, 0x4c, 0x85, 0xda, 0xaa, 0x24,
+	0x4c, 0x27, 0xb6, 0x9b, 0x4e, 0x4c, 0x61, 0x68, 0x8c, 0x85, 0xea, 0x51, 0x1a, 0xf2, 0xcf, 0x68,
+	0x9d, 0x63, 0x4f, 0x12, 0xb8, 0x82, 0x38, 0x4a, 0x9f, 0x94, 0x4e, 0x6b, 0x65, 0x05, 0x1f, 0x47,
+	0xe9, 0x13, 0xbd, 0x02, 0xc2, 0x60, 0xb9, 0x0b, 0x16, 0x51, 0x1c, 0x52, 0x20, 0xb4, 0x65, 0x31,
+	0xac, 0x18, 0xee, 0x43, 0x18, 0x1a, 0x9a, 0x66, 0x8b, 0x60, 0xcb, 0x16, 0x81, 0x41, 0x67, 0x81,
+	0x9a, 0xb2, 0x9e, 0xd3, 0x37, 0x2e, 0x3f, 0x90, 0x2d, 0x09, 0x0d, 0x38, 0xf0, 0x34, 0xe9, 0xfe,
+	0xde, 0x82, 0xde, 0xbd, 0x2c, 0xde, 0x64, 0xe1, 0xeb, 0xd0, 0xe7, 0xa9, 0x28, 0x22, 0xde, 0x58,
+	0xba, 0x54, 0xa0, 0x1e, 0x45, 0x3b, 0x4e, 0xa1, 0xd8, 0x14, 0xfa, 0x33, 0x92, 0x96, 0xea, 0xb4,
+	0x6e, 0xd5, 0x0a, 0xc6, 0x56, 0x35, 0xc8, 0x34, 0x6c, 0xa7, 0x69, 0xd8, 0xbf, 0x5a, 0x30, 0x34,
+	0x26, 0xaa, 0xf6, 0x65, 0xad, 0xdf, 0x57, 0xab, 0xb1, 0x2f, 0xf6, 0x23, 0x18, 0x14, 0x3c, 0xe0,
+	0xd1, 0x89, 0xda, 0xf2, 0xab, 0x66, 0x84, 0x4a, 0x0b, 0x3d, 0x39, 0x8b, 0xfd, 0xb9, 0x3c, 0x3e,
+	0xb6, 0x27, 0x09, 0x3c, 0x88, 0x65, 0xb6, 0x2c, 0x02, 0xfe, 0xd8, 0x17, 0x0b, 0xd5, 0xaa, 0x18,
+	0x1c, 0xf7, 0x06, 0x40, 0xbd, 0xd9, 0xb5, 0x26, 0x5d, 0xe3, 0x1f, 0xf7, 0x57, 0xd0, 0x3b, 0x5a,
+	0x14, 0xdc, 0x0f, 0x5f, 0x90, 0x29, 0xf7, 0xa0, 0x53, 0x64, 0x99, 0x8c, 0x4b, 0x65, 0x56, 0xa9,
+	0x83, 0x31, 0xae, 0x93, 0x25, 0x22, 0xea, 0x10, 0x6e, 0x6f, 0x08, 0xe1, 0x33, 0x96, 0xce, 0x01,
+	0xea, 0x91, 0xd6, 0xda, 0xb9, 0xd1, 0x9f, 0xb5, 0xce, 0xf6, 0x67, 0xdf, 0x83, 0x01, 0xc5, 0x67,
+	0xc1, 0x53, 0xd3, 0xe9, 0x2b, 0xab, 0xab, 0x50, 0xee, 0xcf, 0x61, 0xf4, 0x50, 0xa9, 0xd3, 0xf9,
+	0xb8, 0x59, 0x87, 0x99, 0x3c, 0xa3, 0x6f, 0xe2, 0x00, 0x26, 0x64, 0x6d, 0xb0, 0x6d, 0xec, 0xa3,
+	0xdd, 0x5f, 0xc0, 0x1b, 0x2b, 0xda, 0xcd, 0x5d, 0x58, 0x67, 0x77, 0x71, 0x0d, 0x7a, 0x41, 0x96,
+	0x9f, 0x89, 0x74, 0xd2, 0xe6, 0xe1, 0x9d, 0x2c, 0xd7, 0x93, 0x2b, 0x10, 0x26, 0xef, 0xa1, 0x21,
+	0xdd, 0x64, 0x36, 0xec, 0x78, 0xea, 0xa4, 0x6d, 0x7b, 0x35, 0x83, 0xbd, 0x03, 0xe3, 0x20, 0x4b,
+	0x05, 0x4f, 0x85, 0x42, 0xc8, 0xb3, 0xde, 0x64, 0xe2, 0x18, 0x27, 0x7e, 0x11, 0xf9, 0xa9, 0x78,
+	0x34, 0x23, 0xc7, 0xd9, 0x5e, 0xcd, 0x70, 0x6f, 0xc1, 0xc8, 0xec, 0x1b, 0xd6, 0x06, 0xdc, 0x45,
+	0xe8, 0x96, 0xe2, 0x34, 0x96, 0x77, 0x86, 0xae, 0x27, 0x09, 0xf7, 0xfb, 0x30, 0xd0, 0xad, 0x09,
+	0xbb, 0x06, 0x83, 0x52, 0x7d, 0x3b, 0x56, 0x5d, 0x2c, 0x94, 0x5c, 0x7b, 0x4f, 0x43, 0xdc, 0x3f,
+	0x59, 0xd0, 0x57, 0xb2, 0xaa, 0x04, 0xa8, 0x09, 0xf1, 0x1b, 0xcf, 0xc8, 0x2f, 0x97, 0x99, 0xe0,
+	0x32, 0x83, 0xc9, 0x7d, 0x1b, 0x1c, 0xec, 0x63, 0x24, 0xd5, 0xe8, 0x99, 0xe4, 0xee, 0xd7, 0x48,
+	0xb0, 0x30, 0x2e, 0x53, 0xc9, 0x27, 0x0b, 0x0c, 0xbc, 0x8a, 0x46, 0x19, 0xce, 0x49, 0x23, 0xc8,
+	0xd3, 0x58, 0xd1, 0xee, 0xdf, 0x2c, 0x80, 0xba, 0x0f, 0x47, 0xe8, 0x2c, 0x8a, 0xf9, 0x27, 0xb5,
+	0x7d, 0x2a, 0x1a, 0x0b, 0xbc, 0x32, 0xfb, 0xd1, 0x69, 0xae, 0x6f, 0x57, 0x26, 0xab, 0x31, 0x51,
+	0xbb, 0x39, 0x11, 0xfa, 0x88, 0xae, 0x9d, 0x24, 0x54, 0x3e, 0xaa, 0x18, 0x6c, 0x1f, 0x06, 0x09,
+	0x17, 0x3e, 0x99, 0xa9, 0x3b, 0xb1, 0x74, 0xbf, 0x87, 0xbd, 0xfc, 0x43, 0xc5, 0xd7, 0x26, 0xd6,
+	0x38, 0xf7, 0x1f, 0x16, 0x8c, 0xcc, 0x8b, 0x00, 0x15, 0x05, 0xb9, 0x9a, 0x07, 0xa1, 0x8e, 0xdd,
+	0x8a, 0xf1, 0x9a, 0xcb, 0x37, 0x0d, 0xd3, 0x39, 0x63, 0x98, 0xff, 0x63, 0xf1, 0x78, 0x2c, 0xf1,
+	0xf7, 0x27, 0x45, 0x44, 0x9d, 0xd2, 0xc0, 0xd3, 0xa4, 0xfb, 0x9f, 0x16, 0x8c, 0x4c, 0x55, 0x3d,
+	0x35, 0xf5, 0x5d, 0xb2, 0xe7, 0xa8, 0x68, 0xac, 0xee, 0xe5, 0xc2, 0xdf, 0xbf, 0xf9, 0x81, 0xda,
+	0x8f, 0xa2, 0xe8, 0xf2, 0x10, 0x95, 0x79, 0x56, 0x46, 0x42, 0xd7, 0x63, 0xdb, 0x33, 0x59, 0xa8,
+	0x19, 0xa5, 0x71, 0x94, 0x72, 0x15, 0x2e, 0x8a, 0xa2, 0xb6, 0xbe, 0xf0, 0xd3, 0x72, 0x86, 0x35,
+	0x25, 0xc8, 0xc2, 0x28, 0x9d, 0xab, 0xa0, 0x59, 0xe1, 0xb3, 0x03, 0x18, 0x05, 0x05, 0xf7, 0x71,
+	0x3c, 0xba, 0xc1, 0xf4, 0x5e, 0x5a, 0x44, 0x3a, 0x54, 0x40, 0x1a, 0x5a, 0xec, 0x63, 0xb8, 0x90,
+	0x64, 0x61, 0x34, 0x8b, 0x82, 0x7a, 0xa4, 0xfe, 0x2b, 0x8e, 0xb4, 0xa2, 0x69, 0xde, 0xec, 0x07,
+	0x2f, 0xb9, 0xd9, 0xbb, 0x7f, 0x69, 0x83, 0x5d, 0xdd, 0xd5, 0xd9, 0xdb, 0xd0, 0x2b, 0x79, 0x1a,
+	0xf2, 0x42, 0x3d, 0x09, 0x98, 0x3d, 0xb4, 0xa7, 0x44, 0xaf, 0xda, 0x8b, 0x5f, 0x85, 0x7e, 0xa1,
+	0x2e, 0xe3, 0x1b, 0x1b, 0x72, 0x8d, 0x50, 0x8d, 0x7b, 0xe7, 0x45, 0x8d, 0xfb, 0x15, 0x68, 0x05,
+	0x81, 0xd3, 0xdd, 0x08, 0x09, 0x02, 0x6c, 0x64, 0x8f, 0x83, 0xc0, 0xe9, 0x6d, 0xc2, 0xa0, 0x94,
+	0xdd, 0x84, 0xb1, 0x9a, 0xf5, 0xc7, 0x45, 0xb6, 0xcc, 0xe5, 0x5b, 0xc0, 0x70, 0xdf, 0x46, 0x38,
+	0x71, 0xf4, 0xad, 0xaa, 0x81, 0x62, 0x57, 0x61, 0x20, 0xb4, 0xc6, 0x60, 0xbd, 0xc6, 0x40, 0x18,
+	0xe0, 0x20, 0x50, 0x60, 0x7b, 0x03, 0x58, 0x03, 0xd8, 0x35, 0xb0, 0x8f, 0x2b, 0x34, 0xac, 0x47,
+	0xd7, 0x08, 0xf7, 0x26, 0x74, 0xe9, 0x6b, 0x6d, 0x12, 0x77, 0xa0, 0x9f, 0xe0, 0x2b, 0x40, 0x21,
+	0xcb, 0x53, 0xd7, 0xd3, 0xa4, 0xfb, 0xe7, 0x16, 0xf4, 0xe4, 0x95, 0xb8, 0xf9, 0x66, 0x63, 0xbd,
+	0xd2, 0x9b, 0xcd, 0x01, 0xbe, 0x9e, 0xa0, 0x32, 0x85, 0x66, 0xeb, 0x7f, 0xe8, 0x94, 0x0c, 0x3d,
+	0xb6, 0x07, 0xe7, 0x25, 0xa5, 0x8b, 0x6e, 0xa8, 0x8e, 0xe5, 0x59, 0x36, 0x1e, 0xc1, 0x5a, 0x4f,
+	0xbd, 0x1c, 0x74, 0xa8, 0x2e, 0xad, 0xf0, 0xb1, 0x40, 0x16, 0xfe, 0x53, 0xaf, 0x5e, 0x9e, 0x3c,
+	0xab, 0x4d, 0x26, 0xfb, 0x2e, 0xbc, 0x51, 0x6b, 0xea, 0x17, 0x05, 0x99, 0x77, 0x56, 0x05, 0xee,
+	0x6f, 0x2c, 0xe8, 0xa9, 0x5a, 0x79, 0xa3, 0xba, 0x3d, 0xc8, 0x9a, 0xb7, 0x5d, 0x1f, 0xa6, 0xe9,
+	0x4f, 0x49, 0x60, 0x36, 0x1d, 0x0a, 0xbb, 0x73, 0x0f, 0x86, 0x86, 0x70, 0x4d, 0xff, 0x7d, 0xc5,
+	0x7c, 0xa2, 0x53, 0xd1, 0x2a, 0x47, 0x2d, 0xcd, 0xf7, 0xba, 0x2b, 0xd0, 0x57, 0x5c, 0xb6, 0xdd,
+	0x58, 0x48, 0x7d, 0x8d, 0xd9, 0x86, 0x9e, 0x9c, 0x8a, 0x8d, 0xc0, 0x3a, 0x51, 0x42, 0xeb, 0xc4,
+	0x2d, 0xa1, 0xaf, 0x3c, 0xba, 0x29, 0x54, 0x94, 0x7f, 0xf5, 0x9b, 0x9e, 0x22, 0xb1, 0x30, 0xa7,
+	0x59, 0x91, 0xf8, 0x71, 0xf4, 0x39, 0xd7, 0x1e, 0x32, 0x38, 0x98, 0x8d, 0xcb, 0x44, 0xe4, 0x4b,
+	0x31, 0xbb, 0xa5, 0x0b, 0xad, 0xa6, 0xdd, 0x1f, 0xc2, 0xf0, 0x90, 0xfb, 0x45, 0xb0, 0x90, 0x1d,
+	0xdb, 0x45, 0xe8, 0x0a, 0x5e, 0x24, 0xa5, 0x9a, 0x59, 0x12, 0x2f, 0x68, 0xc8, 0x0e, 0x61, 0x4b,
+	0xaa, 0x3f, 0xce, 0x4a, 0x11, 0xa5, 0x73, 0x6a, 0x3a, 0x72, 0xf5, 0x6d, 0x36, 0x1d, 0x4a, 0xae,
+	0x0f, 0x93, 0x86, 0xe0, 0x4e, 0x45, 0xc1, 0x75, 0x6d, 0xa3, 0x6f, 0x37, 0x81, 0xbe, 0x82, 0xaf,
+	0x6d, 0xbf, 0xb6, 0xa1, 0x37, 0x8b, 0x78, 0x1c, 0x96, 0x6a, 0x31, 0x8a, 0x6a, 0xf6, 0xc7, 0x63,
+	0xdd, 0x1f, 0x4f, 0x60, 0x48, 0xf2, 0x3b, 0x48, 0xc9, 0xae, 0x7f, 0xec, 0x99, 0x2c, 0xf7, 0x67,
+	0x30, 0xd6, 0x85, 0x4b, 0x1a, 0x01, 0x1f, 0xb8, 0x78, 0x90, 0x15, 0xa1, 0x36, 0x83, 0x26, 0x37,
+	0x1b, 0x02, 0x27, 0x0f, 0x7d, 0xa1, 0xde, 0x54, 0x6d, 0x4f, 0x12, 0xee, 0xdf, 0x5b, 0xb0, 0xa5,
+	0xc7, 0xf6, 0x68, 0x8c, 0xb5, 0x3b, 0xc2, 0x35, 0x16, 0x59, 0x72, 0x90, 0x25, 0x7e, 0x94, 0xea,
+	0x5b, 0xaf, 0xc9, 0xc2, 0x3e, 0x41, 0x64, 0x5a, 0xde, 0x26, 0x79, 0xcd, 0x40, 0x69, 0x10, 0x68,
+	0xa9, 0xbc, 0xd7, 0xd4, 0x8c, 0xea, 0x05, 0xa5, 0xfb, 0xba, 0x2f, 0x28, 0xbd, 0x97, 0xbe, 0xa0,
+	0xf4, 0x57, 0x5f, 0x50, 0xf6, 0xe0, 0x7c, 0x4d, 0x62, 0xcf, 0x22, 0xd3, 0xb1, 0xed, 0x9d, 0x65,
+	0xa3, 0x71, 0x75, 0x19, 0xb4, 0x09, 0xa1, 0x49, 0xf7, 0x29, 0xd8, 0x78, 0xd8, 0xe5, 0xd1, 0xd4,
+	0xdb, 0xb1, 0x5e, 0xef, 0x39, 0x7a, 0xe5, 0xba, 0xa3, 0x1d, 0xd3, 0x36, 0x2e, 0x70, 0x7f, 0xb0,
+	0x60, 0x70, 0x54, 0x70, 0xf9, 0xde, 0x70, 0x11, 0xba, 0x31, 0x3f, 0xe1, 0xb1, 0x7e, 0x03, 0x20,
+	0x82, 0x5d, 0x3b, 0x7b, 0x95, 0xa6, 0xd4, 0x8c, 0x4a, 0x6b, 0xef, 0x36, 0xd7, 0x57, 0x2e, 0x55,
+	0x15, 0xfe, 0x0e, 0xf2, 0xcf, 0xde, 0xa9, 0x5e, 0x70, 0xbf, 0x7b, 0x1f, 0xec, 0x6a, 0x1a, 0x33,
+	0x61, 0x8d, 0xd6, 0xfc, 0xa7, 0x30, 0x52, 0x39, 0xca, 0x7d, 0x00, 0x76, 0x35, 0xd7, 0xda, 0x58,
+	0x64, 0xd0, 0x89, 0xfd, 0xea, 0x31, 0x8a, 0xbe, 0xd7, 0xdf, 0x3c, 0x6f, 0x5f, 0xfa, 0xf2, 0xd9,
+	0xae, 0xf5, 0xd5, 0xb3, 0x5d, 0xeb, 0xeb, 0x67, 0xbb, 0xd6, 0xbf, 0x9f, 0xed, 0x5a, 0x5f, 0x3c,
+	0xdf, 0x3d, 0xf7, 0xd5, 0xf3, 0xdd, 0x73, 0x5f, 0x3f, 0xdf, 0x3d, 0x77, 0xdc, 0x23, 0x87, 0xbc,
+	0xff, 0xdf, 0x01, 0x00, 0x01, 0xae, 0x79, 0xa6, 0xba, 0x19, 0x00, 0x00,
+}
+
+func (m *ChunkedEmail) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ChunkedEmail) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ChunkedEmail) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Parts) > 0 {
+		keysForParts := make([]int32, 0, len(m.Parts))
+		for k := range m.Parts {
+			keysForParts = append(keysForParts, int32(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Int32s(keysForParts)
+		for iNdEx := len(keysForParts) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Parts[int32(keysForParts[iNdEx])]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintEmail(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i = encodeVarintEmail(dAtA, i, uint64(keysForParts[iNdEx]))
+			i--
+			dAtA[i] = 0x8
+			i = encodeVarintEmail(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Email) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Email) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Email) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.SummaryHash) > 0 {
+		i -= len(m.SummaryHash)
+		copy(dAtA[i:], m.SummaryHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.SummaryHash)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xfa
+	}
+	if m.Delta != nil {
+		{
+			size, err := m.Delta.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintEmail(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xf2
+	}
+	if len(m.CoreHash) > 0 {
+		i -= len(m.CoreHash)
+		copy(dAtA[i:], m.CoreHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.CoreHash)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xea
+	}
+	if m.Version != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xe0
+	}
+	if len(m.HeaderTemplateHash) > 0 {
+		i -= len(m.HeaderTemplateHash)
+		copy(dAtA[i:], m.HeaderTemplateHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.HeaderTemplateHash)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xda
+	}
+	if len(m.ElidedHeaders) > 0 {
+		for iNdEx := len(m.ElidedHeaders) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.ElidedHeaders[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0xd2
+		}
+	}
+	if len(m.HtmlSegmentsHash) > 0 {
+		i -= len(m.HtmlSegmentsHash)
+		copy(dAtA[i:], m.HtmlSegmentsHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.HtmlSegmentsHash)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xca
+	}
+	if len(m.TextSegmentsHash) > 0 {
+		i -= len(m.TextSegmentsHash)
+		copy(dAtA[i:], m.TextSegmentsHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.TextSegmentsHash)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc2
+	}
+	if len(m.HtmlBodyHash) > 0 {
+		i -= len(m.HtmlBodyHash)
+		copy(dAtA[i:], m.HtmlBodyHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.HtmlBodyHash)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xba
+	}
+	if len(m.TextBodyHash) > 0 {
+		i -= len(m.TextBodyHash)
+		copy(dAtA[i:], m.TextBodyHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.TextBodyHash)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb2
+	}
+	if len(m.ResentBlocks) > 0 {
+		for iNdEx := len(m.ResentBlocks) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.ResentBlocks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0xaa
+		}
+	}
+	if m.DateInvalid {
+		i--
+		if m.DateInvalid {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa0
+	}
+	if len(m.RawDate) > 0 {
+		i -= len(m.RawDate)
+		copy(dAtA[i:], m.RawDate)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.RawDate)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x9a
+	}
+	if m.DateOffset != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.DateOffset))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x90
+	}
+	if len(m.HtmlBodyRaw) > 0 {
+		i -= len(m.HtmlBodyRaw)
+		copy(dAtA[i:], m.HtmlBodyRaw)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.HtmlBodyRaw)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x8a
+	}
+	if len(m.TextBodyRaw) > 0 {
+		i -= len(m.TextBodyRaw)
+		copy(dAtA[i:], m.TextBodyRaw)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.TextBodyRaw)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x82
+	}
+	if len(m.HtmlCharset) > 0 {
+		i -= len(m.HtmlCharset)
+		copy(dAtA[i:], m.HtmlCharset)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.HtmlCharset)))
+		i--
+		dAtA[i] = 0x7a
+	}
+	if len(m.TextCharset) > 0 {
+		i -= len(m.TextCharset)
+		copy(dAtA[i:], m.TextCharset)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.TextCharset)))
+		i--
+		dAtA[i] = 0x72
+	}
+	if len(m.EmbeddedFiles) > 0 {
+		for iNdEx := len(m.EmbeddedFiles) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.EmbeddedFiles[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x6a
+		}
+	}
+	if len(m.Attachments) > 0 {
+		for iNdEx := len(m.Attachments) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Attachments[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x62
+		}
+	}
+	if len(m.TextBody) > 0 {
+		i -= len(m.TextBody)
+		copy(dAtA[i:], m.TextBody)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.TextBody)))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if len(m.HtmlBody) > 0 {
+		i -= len(m.HtmlBody)
+		copy(dAtA[i:], m.HtmlBody)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.HtmlBody)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.Resent != nil {
+		{
+			size, err := m.Resent.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintEmail(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.References) > 0 {
+		for iNdEx := len(m.References) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.References[iNdEx])
+			copy(dAtA[i:], m.References[iNdEx])
+			i = encodeVarintEmail(dAtA, i, uint64(len(m.References[iNdEx])))
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if len(m.InReplyTo) > 0 {
+		for iNdEx := len(m.InReplyTo) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.InReplyTo[iNdEx])
+			copy(dAtA[i:], m.InReplyTo[iNdEx])
+			i = encodeVarintEmail(dAtA, i, uint64(len(m.InReplyTo[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if len(m.MessageID) > 0 {
+		i -= len(m.MessageID)
+		copy(dAtA[i:], m.MessageID)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.MessageID)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	n3, err3 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Date, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Date):])
+	if err3 != nil {
+		return 0, err3
+	}
+	i -= n3
+	i = encodeVarintEmail(dAtA, i, uint64(n3))
+	i--
+	dAtA[i] = 0x22
+	{
+		size, err := m.Addresses.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintEmail(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	if len(m.Subject) > 0 {
+		i -= len(m.Subject)
+		copy(dAtA[i:], m.Subject)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Subject)))
+		i--
+		dAtA[i] = 0x12
+	}
+	{
+		size, err := m.Headers.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintEmail(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *Summary) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Summary) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Summary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x40
+	}
+	if len(m.Snippet) > 0 {
+		i -= len(m.Snippet)
+		copy(dAtA[i:], m.Snippet)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Snippet)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.Attachments != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Attachments))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.MessageSize != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.MessageSize))
+		i--
+		dAtA[i] = 0x28
+	}
+	n6, err6 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Date, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Date):])
+	if err6 != nil {
+		return 0, err6
+	}
+	i -= n6
+	i = encodeVarintEmail(dAtA, i, uint64(n6))
+	i--
+	dAtA[i] = 0x22
+	if len(m.To) > 0 {
+		for iNdEx := len(m.To) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.To[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.From) > 0 {
+		for iNdEx := len(m.From) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.From[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Subject) > 0 {
+		i -= len(m.Subject)
+		copy(dAtA[i:], m.Subject)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Subject)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *EmailDelta) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EmailDelta) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *EmailDelta) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Ops) > 0 {
+		for iNdEx := len(m.Ops) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Ops[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if m.Depth != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Depth))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.BaseDigest) > 0 {
+		i -= len(m.BaseDigest)
+		copy(dAtA[i:], m.BaseDigest)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.BaseDigest)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.BaseHash) > 0 {
+		i -= len(m.BaseHash)
+		copy(dAtA[i:], m.BaseHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.BaseHash)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DeltaOp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeltaOp) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DeltaOp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Length != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Length))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Offset != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *HeaderTemplate) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HeaderTemplate) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *HeaderTemplate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Headers) > 0 {
+		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Headers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TemplateHeader) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TemplateHeader) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TemplateHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Values[iNdEx])
+			copy(dAtA[i:], m.Values[iNdEx])
+			i = encodeVarintEmail(dAtA, i, uint64(len(m.Values[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ArchiveNode) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ArchiveNode) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ArchiveNode) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Count != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Slots) > 0 {
+		for iNdEx := len(m.Slots) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Slots[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ArchiveSlot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ArchiveSlot) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ArchiveSlot) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ChildHash) > 0 {
+		i -= len(m.ChildHash)
+		copy(dAtA[i:], m.ChildHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.ChildHash)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Links) > 0 {
+		for iNdEx := len(m.Links) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Links[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Index != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Index))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ArchiveLink) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ArchiveLink) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ArchiveLink) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Chunked {
+		i--
+		if m.Chunked {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Folder) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Folder) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Folder) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Folders) > 0 {
+		for iNdEx := len(m.Folders) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Folders[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Entries) > 0 {
+		for iNdEx := len(m.Entries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Entries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FolderEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FolderEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FolderEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.SourcePath) > 0 {
+		i -= len(m.SourcePath)
+		copy(dAtA[i:], m.SourcePath)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.SourcePath)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Flags) > 0 {
+		for iNdEx := len(m.Flags) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Flags[iNdEx])
+			copy(dAtA[i:], m.Flags[iNdEx])
+			i = encodeVarintEmail(dAtA, i, uint64(len(m.Flags[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	n7, err7 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Received, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Received):])
+	if err7 != nil {
+		return 0, err7
+	}
+	i -= n7
+	i = encodeVarintEmail(dAtA, i, uint64(n7))
+	i--
+	dAtA[i] = 0x1a
+	if m.Chunked {
+		i--
+		if m.Chunked {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FolderLink) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FolderLink) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FolderLink) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Thread) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Thread) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Thread) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Count != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x18
+	}
+	{
+		size, err := m.Root.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintEmail(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	if len(m.Subject) > 0 {
+		i -= len(m.Subject)
+		copy(dAtA[i:], m.Subject)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Subject)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ThreadNode) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ThreadNode) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ThreadNode) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Children) > 0 {
+		for iNdEx := len(m.Children) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Children[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.MessageID) > 0 {
+		i -= len(m.MessageID)
+		copy(dAtA[i:], m.MessageID)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.MessageID)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MessageIndex) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MessageIndex) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MessageIndex) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Entries) > 0 {
+		for iNdEx := len(m.Entries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Entries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MessageIndexEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MessageIndexEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MessageIndexEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Copies) > 0 {
+		for iNdEx := len(m.Copies) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Copies[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.MessageID) > 0 {
+		i -= len(m.MessageID)
+		copy(dAtA[i:], m.MessageID)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.MessageID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *IndexedCopy) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *IndexedCopy) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *IndexedCopy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.VariantOf) > 0 {
+		i -= len(m.VariantOf)
+		copy(dAtA[i:], m.VariantOf)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.VariantOf)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.ContentDigest) > 0 {
+		i -= len(m.ContentDigest)
+		copy(dAtA[i:], m.ContentDigest)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.ContentDigest)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.RawDigest) > 0 {
+		i -= len(m.RawDigest)
+		copy(dAtA[i:], m.RawDigest)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.RawDigest)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ElidedHeader) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ElidedHeader) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ElidedHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Style != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Style))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Segments) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Segments) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Segments) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Segments) > 0 {
+		for iNdEx := len(m.Segments) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Segments[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Segment) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Segment) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Segment) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.DataHash) > 0 {
+		i -= len(m.DataHash)
+		copy(dAtA[i:], m.DataHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.DataHash)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Unquoted {
+		i--
+		if m.Unquoted {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.QuotedSegmentsHash) > 0 {
+		i -= len(m.QuotedSegmentsHash)
+		copy(dAtA[i:], m.QuotedSegmentsHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.QuotedSegmentsHash)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.QuotedHash) > 0 {
+		i -= len(m.QuotedHash)
+		copy(dAtA[i:], m.QuotedHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.QuotedHash)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Attachment) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Attachment) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Attachment) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.Metadata.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintEmail(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x2a
+	if len(m.EmailHash) > 0 {
+		i -= len(m.EmailHash)
+		copy(dAtA[i:], m.EmailHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.EmailHash)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.DataHash) > 0 {
+		i -= len(m.DataHash)
+		copy(dAtA[i:], m.DataHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.DataHash)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.ContentType) > 0 {
+		i -= len(m.ContentType)
+		copy(dAtA[i:], m.ContentType)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.ContentType)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.FileName) > 0 {
+		i -= len(m.FileName)
+		copy(dAtA[i:], m.FileName)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.FileName)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *EmbeddedFile) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EmbeddedFile) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *EmbeddedFile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.DataUri {
+		i--
+		if m.DataUri {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	{
+		size, err := m.Metadata.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintEmail(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x2a
+	if len(m.FileName) > 0 {
+		i -= len(m.FileName)
+		copy(dAtA[i:], m.FileName)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.FileName)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.DataHash) > 0 {
+		i -= len(m.DataHash)
+		copy(dAtA[i:], m.DataHash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.DataHash)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.ContentType) > 0 {
+		i -= len(m.ContentType)
+		copy(dAtA[i:], m.ContentType)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.ContentType)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ContentId) > 0 {
+		i -= len(m.ContentId)
+		copy(dAtA[i:], m.ContentId)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.ContentId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FileMetadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileMetadata) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.Headers.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintEmail(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x42
+	if m.ModificationDate != nil {
+		n12, err12 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.ModificationDate, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.ModificationDate):])
+		if err12 != nil {
+			return 0, err12
+		}
+		i -= n12
+		i = encodeVarintEmail(dAtA, i, uint64(n12))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.CreationDate != nil {
+		n13, err13 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.CreationDate, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.CreationDate):])
+		if err13 != nil {
+			return 0, err13
+		}
+		i -= n13
+		i = encodeVarintEmail(dAtA, i, uint64(n13))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.TransferEncoding) > 0 {
+		i -= len(m.TransferEncoding)
+		copy(dAtA[i:], m.TransferEncoding)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.TransferEncoding)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Inline {
+		i--
+		if m.Inline {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Disposition) > 0 {
+		i -= len(m.Disposition)
+		copy(dAtA[i:], m.Disposition)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Disposition)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Sha256) > 0 {
+		i -= len(m.Sha256)
+		copy(dAtA[i:], m.Sha256)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Sha256)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.FileSize != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.FileSize))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Addresses) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Addresses) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Addresses) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.BccGroups) > 0 {
+		for iNdEx := len(m.BccGroups) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.BccGroups[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x52
+		}
+	}
+	if len(m.CcGroups) > 0 {
+		for iNdEx := len(m.CcGroups) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.CcGroups[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if len(m.ToGroups) > 0 {
+		for iNdEx := len(m.ToGroups) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.ToGroups[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if len(m.ReplyToGroups) > 0 {
+		for iNdEx := len(m.ReplyToGroups) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.ReplyToGroups[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if len(m.Bcc) > 0 {
+		for iNdEx := len(m.Bcc) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Bcc[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if len(m.Cc) > 0 {
+		for iNdEx := len(m.Cc) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Cc[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.To) > 0 {
+		for iNdEx := len(m.To) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.To[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.ReplyTo) > 0 {
+		for iNdEx := len(m.ReplyTo) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.ReplyTo[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.From) > 0 {
+		for iNdEx := len(m.From) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.From[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Sender != nil {
+		{
+			size, err := m.Sender.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintEmail(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Group) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Group) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Group) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Members) > 0 {
+		dAtA16 := make([]byte, len(m.Members)*10)
+		var j15 int
+		for _, num1 := range m.Members {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA16[j15] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j15++
+			}
+			dAtA16[j15] = uint8(num)
+			j15++
+		}
+		i -= j15
+		copy(dAtA[i:], dAtA16[:j15])
+		i = encodeVarintEmail(dAtA, i, uint64(j15))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Resent) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Resent) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Resent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ResentDateInvalid {
+		i--
+		if m.ResentDateInvalid {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.RawResentDate) > 0 {
+		i -= len(m.RawResentDate)
+		copy(dAtA[i:], m.RawResentDate)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.RawResentDate)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.ResentDateOffset != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.ResentDateOffset))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.ResentMessageId) > 0 {
+		i -= len(m.ResentMessageId)
+		copy(dAtA[i:], m.ResentMessageId)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.ResentMessageId)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	n17, err17 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.ResentDate, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.ResentDate):])
+	if err17 != nil {
+		return 0, err17
+	}
+	i -= n17
+	i = encodeVarintEmail(dAtA, i, uint64(n17))
+	i--
+	dAtA[i] = 0x12
+	{
+		size, err := m.Addresses.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintEmail(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *Header) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Header) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Header) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		keysForValues := make([]string, 0, len(m.Values))
+		for k := range m.Values {
+			keysForValues = append(keysForValues, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForValues)
+		for iNdEx := len(keysForValues) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Values[string(keysForValues[iNdEx])]
+			baseI := i
+			{
+				size, err := (&v).MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForValues[iNdEx])
+			copy(dAtA[i:], keysForValues[iNdEx])
+			i = encodeVarintEmail(dAtA, i, uint64(len(keysForValues[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintEmail(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Headers) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Headers) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Headers) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Values[iNdEx])
+			copy(dAtA[i:], m.Values[iNdEx])
+			i = encodeVarintEmail(dAtA, i, uint64(len(m.Values[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Values) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Values) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Values) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.V) > 0 {
+		for iNdEx := len(m.V) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.V[iNdEx])
+			copy(dAtA[i:], m.V[iNdEx])
+			i = encodeVarintEmail(dAtA, i, uint64(len(m.V[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Address) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Address) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Address) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Smtputf8 {
+		i--
+		if m.Smtputf8 {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Normalized) > 0 {
+		i -= len(m.Normalized)
+		copy(dAtA[i:], m.Normalized)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Normalized)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Address) > 0 {
+		i -= len(m.Address)
+		copy(dAtA[i:], m.Address)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Address)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchIndex) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchIndex) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchIndex) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Terms) > 0 {
+		i -= len(m.Terms)
+		copy(dAtA[i:], m.Terms)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Terms)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchPostings) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchPostings) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchPostings) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Tree) > 0 {
+		i -= len(m.Tree)
+		copy(dAtA[i:], m.Tree)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Tree)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Postings) > 0 {
+		for iNdEx := len(m.Postings) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Postings[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Posting) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Posting) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Posting) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.FieldCounts) > 0 {
+		dAtA21 := make([]byte, len(m.FieldCounts)*10)
+		var j20 int
+		for _, num := range m.FieldCounts {
+			for num >= 1<<7 {
+				dAtA21[j20] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j20++
+			}
+			dAtA21[j20] = uint8(num)
+			j20++
+		}
+		i -= j20
+		copy(dAtA[i:], dAtA21[:j20])
+		i = encodeVarintEmail(dAtA, i, uint64(j20))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Count != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Fields != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Fields))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MetadataIndex) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MetadataIndex) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MetadataIndex) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Dates) > 0 {
+		i -= len(m.Dates)
+		copy(dAtA[i:], m.Dates)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Dates)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Version != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Records) > 0 {
+		i -= len(m.Records)
+		copy(dAtA[i:], m.Records)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Records)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MetadataRecord) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MetadataRecord) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MetadataRecord) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Headers) > 0 {
+		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Headers[iNdEx])
+			copy(dAtA[i:], m.Headers[iNdEx])
+			i = encodeVarintEmail(dAtA, i, uint64(len(m.Headers[iNdEx])))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if len(m.AttachmentTypes) > 0 {
+		for iNdEx := len(m.AttachmentTypes) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AttachmentTypes[iNdEx])
+			copy(dAtA[i:], m.AttachmentTypes[iNdEx])
+			i = encodeVarintEmail(dAtA, i, uint64(len(m.AttachmentTypes[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if m.Attachments != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Attachments))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.MessageSize != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.MessageSize))
+		i--
+		dAtA[i] = 0x30
+	}
+	n22, err22 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Date, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Date):])
+	if err22 != nil {
+		return 0, err22
+	}
+	i -= n22
+	i = encodeVarintEmail(dAtA, i, uint64(n22))
+	i--
+	dAtA[i] = 0x2a
+	if len(m.CcDomains) > 0 {
+		for iNdEx := len(m.CcDomains) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.CcDomains[iNdEx])
+			copy(dAtA[i:], m.CcDomains[iNdEx])
+			i = encodeVarintEmail(dAtA, i, uint64(len(m.CcDomains[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.ToDomains) > 0 {
+		for iNdEx := len(m.ToDomains) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ToDomains[iNdEx])
+			copy(dAtA[i:], m.ToDomains[iNdEx])
+			i = encodeVarintEmail(dAtA, i, uint64(len(m.ToDomains[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.FromDomains) > 0 {
+		for iNdEx := len(m.FromDomains) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.FromDomains[iNdEx])
+			copy(dAtA[i:], m.FromDomains[iNdEx])
+			i = encodeVarintEmail(dAtA, i, uint64(len(m.FromDomains[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DateEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DateEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DateEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.MessageID) > 0 {
+		i -= len(m.MessageID)
+		copy(dAtA[i:], m.MessageID)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.MessageID)))
+		i--
+		dAtA[i] = 0x12
+	}
+	n23, err23 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Date, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Date):])
+	if err23 != nil {
+		return 0, err23
+	}
+	i -= n23
+	i = encodeVarintEmail(dAtA, i, uint64(n23))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *TreeNode) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TreeNode) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TreeNode) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Children) > 0 {
+		for iNdEx := len(m.Children) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Children[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Entries) > 0 {
+		for iNdEx := len(m.Entries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Entries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEmail(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Level != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Level))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TreeEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TreeEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TreeEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TreeChild) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TreeChild) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TreeChild) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Count != 0 {
+		i = encodeVarintEmail(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Last) > 0 {
+		i -= len(m.Last)
+		copy(dAtA[i:], m.Last)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Last)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintEmail(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintEmail(dAtA []byte, offset int, v uint64) int {
+	offset -= sovEmail(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *ChunkedEmail) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Parts) > 0 {
+		for k, v := range m.Parts {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + sovEmail(uint64(k)) + 1 + len(v) + sovEmail(uint64(len(v)))
+			n += mapEntrySize + 1 + sovEmail(uint64(mapEntrySize))
+		}
+	}
+	if m.Version != 0 {
+		n += 1 + sovEmail(uint64(m.Version))
+	}
+	return n
+}
+
+func (m *Email) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Headers.Size()
+	n += 1 + l + sovEmail(uint64(l))
+	l = len(m.Subject)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = m.Addresses.Size()
+	n += 1 + l + sovEmail(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Date)
+	n += 1 + l + sovEmail(uint64(l))
+	l = len(m.MessageID)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if len(m.InReplyTo) > 0 {
+		for _, s := range m.InReplyTo {
+			l = len(s)
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.References) > 0 {
+		for _, s := range m.References {
+			l = len(s)
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if m.Resent != nil {
+		l = m.Resent.Size()
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.HtmlBody)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.TextBody)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if len(m.Attachments) > 0 {
+		for _, e := range m.Attachments {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.EmbeddedFiles) > 0 {
+		for _, e := range m.EmbeddedFiles {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	l = len(m.TextCharset)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.HtmlCharset)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.TextBodyRaw)
+	if l > 0 {
+		n += 2 + l + sovEmail(uint64(l))
+	}
+	l = len(m.HtmlBodyRaw)
+	if l > 0 {
+		n += 2 + l + sovEmail(uint64(l))
+	}
+	if m.DateOffset != 0 {
+		n += 2 + sovEmail(uint64(m.DateOffset))
+	}
+	l = len(m.RawDate)
+	if l > 0 {
+		n += 2 + l + sovEmail(uint64(l))
+	}
+	if m.DateInvalid {
+		n += 3
+	}
+	if len(m.ResentBlocks) > 0 {
+		for _, e := range m.ResentBlocks {
+			l = e.Size()
+			n += 2 + l + sovEmail(uint64(l))
+		}
+	}
+	l = len(m.TextBodyHash)
+	if l > 0 {
+		n += 2 + l + sovEmail(uint64(l))
+	}
+	l = len(m.HtmlBodyHash)
+	if l > 0 {
+		n += 2 + l + sovEmail(uint64(l))
+	}
+	l = len(m.TextSegmentsHash)
+	if l > 0 {
+		n += 2 + l + sovEmail(uint64(l))
+	}
+	l = len(m.HtmlSegmentsHash)
+	if l > 0 {
+		n += 2 + l + sovEmail(uint64(l))
+	}
+	if len(m.ElidedHeaders) > 0 {
+		for _, e := range m.ElidedHeaders {
+			l = e.Size()
+			n += 2 + l + sovEmail(uint64(l))
+		}
+	}
+	l = len(m.HeaderTemplateHash)
+	if l > 0 {
+		n += 2 + l + sovEmail(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 2 + sovEmail(uint64(m.Version))
+	}
+	l = len(m.CoreHash)
+	if l > 0 {
+		n += 2 + l + sovEmail(uint64(l))
+	}
+	if m.Delta != nil {
+		l = m.Delta.Size()
+		n += 2 + l + sovEmail(uint64(l))
+	}
+	l = len(m.SummaryHash)
+	if l > 0 {
+		n += 2 + l + sovEmail(uint64(l))
+	}
+	return n
+}
+
+func (m *Summary) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Subject)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if len(m.From) > 0 {
+		for _, e := range m.From {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.To) > 0 {
+		for _, e := range m.To {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Date)
+	n += 1 + l + sovEmail(uint64(l))
+	if m.MessageSize != 0 {
+		n += 1 + sovEmail(uint64(m.MessageSize))
+	}
+	if m.Attachments != 0 {
+		n += 1 + sovEmail(uint64(m.Attachments))
+	}
+	l = len(m.Snippet)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 1 + sovEmail(uint64(m.Version))
+	}
+	return n
+}
+
+func (m *EmailDelta) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.BaseHash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.BaseDigest)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.Depth != 0 {
+		n += 1 + sovEmail(uint64(m.Depth))
+	}
+	if len(m.Ops) > 0 {
+		for _, e := range m.Ops {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DeltaOp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Offset != 0 {
+		n += 1 + sovEmail(uint64(m.Offset))
+	}
+	if m.Length != 0 {
+		n += 1 + sovEmail(uint64(m.Length))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	return n
+}
+
+func (m *HeaderTemplate) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Headers) > 0 {
+		for _, e := range m.Headers {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TemplateHeader) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			l = len(s)
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ArchiveNode) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Slots) > 0 {
+		for _, e := range m.Slots {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if m.Count != 0 {
+		n += 1 + sovEmail(uint64(m.Count))
+	}
+	if m.Version != 0 {
+		n += 1 + sovEmail(uint64(m.Version))
+	}
+	return n
+}
+
+func (m *ArchiveSlot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Index != 0 {
+		n += 1 + sovEmail(uint64(m.Index))
+	}
+	if len(m.Links) > 0 {
+		for _, e := range m.Links {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	l = len(m.ChildHash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	return n
+}
+
+func (m *ArchiveLink) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.Chunked {
+		n += 2
+	}
+	return n
+}
+
+func (m *Folder) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if len(m.Entries) > 0 {
+		for _, e := range m.Entries {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.Folders) > 0 {
+		for _, e := range m.Folders {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if m.Version != 0 {
+		n += 1 + sovEmail(uint64(m.Version))
+	}
+	return n
+}
+
+func (m *FolderEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.Chunked {
+		n += 2
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Received)
+	n += 1 + l + sovEmail(uint64(l))
+	if len(m.Flags) > 0 {
+		for _, s := range m.Flags {
+			l = len(s)
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	l = len(m.SourcePath)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	return n
+}
+
+func (m *FolderLink) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	return n
+}
+
+func (m *Thread) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Subject)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = m.Root.Size()
+	n += 1 + l + sovEmail(uint64(l))
+	if m.Count != 0 {
+		n += 1 + sovEmail(uint64(m.Count))
+	}
+	if m.Version != 0 {
+		n += 1 + sovEmail(uint64(m.Version))
+	}
+	return n
+}
+
+func (m *ThreadNode) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.MessageID)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if len(m.Children) > 0 {
+		for _, e := range m.Children {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *MessageIndex) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Entries) > 0 {
+		for _, e := range m.Entries {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if m.Version != 0 {
+		n += 1 + sovEmail(uint64(m.Version))
+	}
+	return n
+}
+
+func (m *MessageIndexEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.MessageID)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if len(m.Copies) > 0 {
+		for _, e := range m.Copies {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *IndexedCopy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.RawDigest)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.ContentDigest)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.VariantOf)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	return n
+}
+
+func (m *ElidedHeader) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.Style != 0 {
+		n += 1 + sovEmail(uint64(m.Style))
+	}
+	return n
+}
+
+func (m *Segments) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Segments) > 0 {
+		for _, e := range m.Segments {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Segment) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.QuotedHash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.QuotedSegmentsHash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.Unquoted {
+		n += 2
+	}
+	l = len(m.DataHash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	return n
+}
+
+func (m *Attachment) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.FileName)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.ContentType)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.DataHash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.EmailHash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = m.Metadata.Size()
+	n += 1 + l + sovEmail(uint64(l))
+	return n
+}
+
+func (m *EmbeddedFile) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ContentId)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.ContentType)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.DataHash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.FileName)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = m.Metadata.Size()
+	n += 1 + l + sovEmail(uint64(l))
+	if m.DataUri {
+		n += 2
+	}
+	return n
+}
+
+func (m *FileMetadata) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.FileSize != 0 {
+		n += 1 + sovEmail(uint64(m.FileSize))
+	}
+	l = len(m.Sha256)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.Disposition)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.Inline {
+		n += 2
+	}
+	l = len(m.TransferEncoding)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.CreationDate != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.CreationDate)
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.ModificationDate != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.ModificationDate)
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = m.Headers.Size()
+	n += 1 + l + sovEmail(uint64(l))
+	return n
+}
+
+func (m *Addresses) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Sender != nil {
+		l = m.Sender.Size()
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if len(m.From) > 0 {
+		for _, e := range m.From {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.ReplyTo) > 0 {
+		for _, e := range m.ReplyTo {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.To) > 0 {
+		for _, e := range m.To {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.Cc) > 0 {
+		for _, e := range m.Cc {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.Bcc) > 0 {
+		for _, e := range m.Bcc {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.ReplyToGroups) > 0 {
+		for _, e := range m.ReplyToGroups {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.ToGroups) > 0 {
+		for _, e := range m.ToGroups {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.CcGroups) > 0 {
+		for _, e := range m.CcGroups {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.BccGroups) > 0 {
+		for _, e := range m.BccGroups {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Group) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if len(m.Members) > 0 {
+		l = 0
+		for _, e := range m.Members {
+			l += sovEmail(uint64(e))
+		}
+		n += 1 + sovEmail(uint64(l)) + l
+	}
+	return n
+}
+
+func (m *Resent) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Addresses.Size()
+	n += 1 + l + sovEmail(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.ResentDate)
+	n += 1 + l + sovEmail(uint64(l))
+	l = len(m.ResentMessageId)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.ResentDateOffset != 0 {
+		n += 1 + sovEmail(uint64(m.ResentDateOffset))
+	}
+	l = len(m.RawResentDate)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.ResentDateInvalid {
+		n += 2
+	}
+	return n
+}
+
+func (m *Header) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for k, v := range m.Values {
+			_ = k
+			_ = v
+			l = v.Size()
+			mapEntrySize := 1 + len(k) + sovEmail(uint64(len(k))) + 1 + l + sovEmail(uint64(l))
+			n += mapEntrySize + 1 + sovEmail(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *Headers) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			l = len(s)
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Values) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.V) > 0 {
+		for _, s := range m.V {
+			l = len(s)
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Address) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.Normalized)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.Smtputf8 {
+		n += 2
+	}
+	return n
+}
+
+func (m *SearchIndex) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Terms)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 1 + sovEmail(uint64(m.Version))
+	}
+	return n
+}
+
+func (m *SearchPostings) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Postings) > 0 {
+		for _, e := range m.Postings {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	l = len(m.Tree)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	return n
+}
+
+func (m *Posting) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.Fields != 0 {
+		n += 1 + sovEmail(uint64(m.Fields))
+	}
+	if m.Count != 0 {
+		n += 1 + sovEmail(uint64(m.Count))
+	}
+	if len(m.FieldCounts) > 0 {
+		l = 0
+		for _, e := range m.FieldCounts {
+			l += sovEmail(uint64(e))
+		}
+		n += 1 + sovEmail(uint64(l)) + l
+	}
+	return n
+}
+
+func (m *MetadataIndex) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Records)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 1 + sovEmail(uint64(m.Version))
+	}
+	l = len(m.Dates)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	return n
+}
+
+func (m *MetadataRecord) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if len(m.FromDomains) > 0 {
+		for _, s := range m.FromDomains {
+			l = len(s)
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.ToDomains) > 0 {
+		for _, s := range m.ToDomains {
+			l = len(s)
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.CcDomains) > 0 {
+		for _, s := range m.CcDomains {
+			l = len(s)
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Date)
+	n += 1 + l + sovEmail(uint64(l))
+	if m.MessageSize != 0 {
+		n += 1 + sovEmail(uint64(m.MessageSize))
+	}
+	if m.Attachments != 0 {
+		n += 1 + sovEmail(uint64(m.Attachments))
+	}
+	if len(m.AttachmentTypes) > 0 {
+		for _, s := range m.AttachmentTypes {
+			l = len(s)
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.Headers) > 0 {
+		for _, s := range m.Headers {
+			l = len(s)
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DateEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Date)
+	n += 1 + l + sovEmail(uint64(l))
+	l = len(m.MessageID)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	return n
+}
+
+func (m *TreeNode) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Level != 0 {
+		n += 1 + sovEmail(uint64(m.Level))
+	}
+	if len(m.Entries) > 0 {
+		for _, e := range m.Entries {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if len(m.Children) > 0 {
+		for _, e := range m.Children {
+			l = e.Size()
+			n += 1 + l + sovEmail(uint64(l))
+		}
+	}
+	if m.Version != 0 {
+		n += 1 + sovEmail(uint64(m.Version))
+	}
+	return n
+}
+
+func (m *TreeEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	return n
+}
+
+func (m *TreeChild) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	l = len(m.Last)
+	if l > 0 {
+		n += 1 + l + sovEmail(uint64(l))
+	}
+	if m.Count != 0 {
+		n += 1 + sovEmail(uint64(m.Count))
+	}
+	return n
+}
+
+func sovEmail(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozEmail(x uint64) (n int) {
+	return sovEmail(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *ChunkedEmail) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ChunkedEmail: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ChunkedEmail: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Parts", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Parts == nil {
+				m.Parts = make(map[int32]string)
+			}
+			var mapkey int32
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowEmail
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowEmail
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapkey |= int32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowEmail
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthEmail
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthEmail
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipEmail(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthEmail
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Parts[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Email) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Email: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Email: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Headers.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Subject", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Subject = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Addresses", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Addresses.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Date", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Date, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MessageID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InReplyTo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.InReplyTo = append(m.InReplyTo, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field References", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.References = append(m.References, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Resent", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Resent == nil {
+				m.Resent = &Resent{}
+			}
+			if err := m.Resent.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HtmlBody", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HtmlBody = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TextBody", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TextBody = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attachments", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attachments = append(m.Attachments, Attachment{})
+			if err := m.Attachments[len(m.Attachments)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EmbeddedFiles", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EmbeddedFiles = append(m.EmbeddedFiles, EmbeddedFile{})
+			if err := m.EmbeddedFiles[len(m.EmbeddedFiles)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TextCharset", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TextCharset = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HtmlCharset", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HtmlCharset = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TextBodyRaw", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TextBodyRaw = append(m.TextBodyRaw[:0], dAtA[iNdEx:postIndex]...)
+			if m.TextBodyRaw == nil {
+				m.TextBodyRaw = []byte{}
+			}
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HtmlBodyRaw", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HtmlBodyRaw = append(m.HtmlBodyRaw[:0], dAtA[iNdEx:postIndex]...)
+			if m.HtmlBodyRaw == nil {
+				m.HtmlBodyRaw = []byte{}
+			}
+			iNdEx = postIndex
+		case 18:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DateOffset", wireType)
+			}
+			m.DateOffset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DateOffset |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RawDate", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RawDate = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 20:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DateInvalid", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DateInvalid = bool(v != 0)
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResentBlocks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ResentBlocks = append(m.ResentBlocks, Resent{})
+			if err := m.ResentBlocks[len(m.ResentBlocks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TextBodyHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TextBodyHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HtmlBodyHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HtmlBodyHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 24:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TextSegmentsHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TextSegmentsHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 25:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HtmlSegmentsHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HtmlSegmentsHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 26:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ElidedHeaders", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ElidedHeaders = append(m.ElidedHeaders, ElidedHeader{})
+			if err := m.ElidedHeaders[len(m.ElidedHeaders)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 27:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeaderTemplateHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HeaderTemplateHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 28:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 29:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CoreHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CoreHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 30:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Delta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Delta == nil {
+				m.Delta = &EmailDelta{}
+			}
+			if err := m.Delta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 31:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SummaryHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SummaryHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Summary) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Summary: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Summary: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Subject", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Subject = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.From = append(m.From, Address{})
+			if err := m.From[len(m.From)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field To", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.To = append(m.To, Address{})
+			if err := m.To[len(m.To)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Date", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Date, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageSize", wireType)
+			}
+			m.MessageSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MessageSize |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attachments", wireType)
+			}
+			m.Attachments = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Attachments |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Snippet", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Snippet = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EmailDelta) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EmailDelta: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EmailDelta: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BaseHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BaseHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BaseDigest", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BaseDigest = append(m.BaseDigest[:0], dAtA[iNdEx:postIndex]...)
+			if m.BaseDigest == nil {
+				m.BaseDigest = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Depth", wireType)
+			}
+			m.Depth = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Depth |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ops", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ops = append(m.Ops, DeltaOp{})
+			if err := m.Ops[len(m.Ops)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DeltaOp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DeltaOp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DeltaOp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Length", wireType)
+			}
+			m.Length = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Length |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HeaderTemplate) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HeaderTemplate: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HeaderTemplate: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Headers = append(m.Headers, TemplateHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TemplateHeader) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TemplateHeader: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TemplateHeader: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Values = append(m.Values, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ArchiveNode) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ArchiveNode: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ArchiveNode: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Slots", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Slots = append(m.Slots, ArchiveSlot{})
+			if err := m.Slots[len(m.Slots)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ArchiveSlot) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ArchiveSlot: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ArchiveSlot: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Links", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Links = append(m.Links, ArchiveLink{})
+			if err := m.Links[len(m.Links)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChildHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChildHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ArchiveLink) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ArchiveLink: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ArchiveLink: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chunked", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Chunked = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Folder) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Folder: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Folder: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Entries", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Entries = append(m.Entries, FolderEntry{})
+			if err := m.Entries[len(m.Entries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Folders", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Folders = append(m.Folders, FolderLink{})
+			if err := m.Folders[len(m.Folders)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FolderEntry) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FolderEntry: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FolderEntry: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chunked", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Chunked = bool(v != 0)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Received", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Received, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Flags", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Flags = append(m.Flags, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SourcePath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SourcePath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FolderLink) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FolderLink: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FolderLink: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Thread) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Thread: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Thread: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Subject", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Subject = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Root", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Root.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ThreadNode) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ThreadNode: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ThreadNode: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MessageID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Children", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Children = append(m.Children, ThreadNode{})
+			if err := m.Children[len(m.Children)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MessageIndex) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MessageIndex: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MessageIndex: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Entries", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Entries = append(m.Entries, MessageIndexEntry{})
+			if err := m.Entries[len(m.Entries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MessageIndexEntry) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MessageIndexEntry: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MessageIndexEntry: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MessageID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Copies", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Copies = append(m.Copies, IndexedCopy{})
+			if err := m.Copies[len(m.Copies)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *IndexedCopy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: IndexedCopy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: IndexedCopy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RawDigest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RawDigest = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentDigest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContentDigest = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VariantOf", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.VariantOf = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ElidedHeader) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ElidedHeader: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ElidedHeader: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Style", wireType)
+			}
+			m.Style = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Style |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Segments) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Segments: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Segments: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Segments", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Segments = append(m.Segments, Segment{})
+			if err := m.Segments[len(m.Segments)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Segment) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Segment: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Segment: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QuotedHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.QuotedHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QuotedSegmentsHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.QuotedSegmentsHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Unquoted", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Unquoted = bool(v != 0)
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DataHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DataHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Attachment) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Attachment: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Attachment: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FileName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FileName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContentType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DataHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DataHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EmailHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EmailHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EmbeddedFile) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EmbeddedFile: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EmbeddedFile: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContentId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContentType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DataHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DataHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FileName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FileName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DataUri", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DataUri = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FileMetadata) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FileMetadata: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FileMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FileSize", wireType)
+			}
+			m.FileSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FileSize |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sha256", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sha256 = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Disposition", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Disposition = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Inline", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Inline = bool(v != 0)
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TransferEncoding", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TransferEncoding = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreationDate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CreationDate == nil {
+				m.CreationDate = new(time.Time)
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.CreationDate, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ModificationDate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ModificationDate == nil {
+				m.ModificationDate = new(time.Time)
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.ModificationDate, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Headers.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Addresses) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Addresses: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Addresses: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Sender == nil {
+				m.Sender = &Address{}
+			}
+			if err := m.Sender.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.From = append(m.From, Address{})
+			if err := m.From[len(m.From)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplyTo", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ReplyTo = append(m.ReplyTo, Address{})
+			if err := m.ReplyTo[len(m.ReplyTo)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field To", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.To = append(m.To, Address{})
+			if err := m.To[len(m.To)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cc", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cc = append(m.Cc, Address{})
+			if err := m.Cc[len(m.Cc)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bcc", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Bcc = append(m.Bcc, Address{})
+			if err := m.Bcc[len(m.Bcc)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplyToGroups", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ReplyToGroups = append(m.ReplyToGroups, Group{})
+			if err := m.ReplyToGroups[len(m.ReplyToGroups)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ToGroups", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ToGroups = append(m.ToGroups, Group{})
+			if err := m.ToGroups[len(m.ToGroups)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CcGroups", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CcGroups = append(m.CcGroups, Group{})
+			if err := m.CcGroups[len(m.CcGroups)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BccGroups", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BccGroups = append(m.BccGroups, Group{})
+			if err := m.BccGroups[len(m.BccGroups)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Group) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Group: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Group: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType == 0 {
+				var v int32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowEmail
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= int32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Members = append(m.Members, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowEmail
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthEmail
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthEmail
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.Members) == 0 {
+					m.Members = make([]int32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowEmail
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Members = append(m.Members, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Members", wireType)
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Resent) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Resent: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Resent: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Addresses", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Addresses.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResentDate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.ResentDate, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResentMessageId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ResentMessageId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResentDateOffset", wireType)
+			}
+			m.ResentDateOffset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ResentDateOffset |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RawResentDate", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RawResentDate = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResentDateInvalid", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ResentDateInvalid = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	return n
-}
 
-func (m *Address) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovEmail(uint64(l))
-	}
-	l = len(m.Address)
-	if l > 0 {
-		n += 1 + l + sovEmail(uint64(l))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-
-func sovEmail(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozEmail(x uint64) (n int) {
-	return sovEmail(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *ChunkedEmail) Unmarshal(dAtA []byte) error {
+func (m *Header) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1577,15 +12026,15 @@ func (m *ChunkedEmail) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ChunkedEmail: wiretype end group for non-group")
+			return fmt.Errorf("proto: Header: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ChunkedEmail: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Header: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Parts", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1612,11 +12061,11 @@ func (m *ChunkedEmail) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Parts == nil {
-				m.Parts = make(map[int32]string)
+			if m.Values == nil {
+				m.Values = make(map[string]Headers)
 			}
-			var mapkey int32
-			var mapvalue string
+			var mapkey string
+			mapvalue := &Headers{}
 			for iNdEx < postIndex {
 				entryPreIndex := iNdEx
 				var wire uint64
@@ -1636,6 +12085,7 @@ func (m *ChunkedEmail) Unmarshal(dAtA []byte) error {
 				}
 				fieldNum := int32(wire >> 3)
 				if fieldNum == 1 {
+					var stringLenmapkey uint64
 					for shift := uint(0); ; shift += 7 {
 						if shift >= 64 {
 							return ErrIntOverflowEmail
@@ -1645,13 +12095,26 @@ func (m *ChunkedEmail) Unmarshal(dAtA []byte) error {
 						}
 						b := dAtA[iNdEx]
 						iNdEx++
-						mapkey |= int32(b&0x7F) << shift
+						stringLenmapkey |= uint64(b&0x7F) << shift
 						if b < 0x80 {
 							break
 						}
 					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthEmail
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthEmail
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
 				} else if fieldNum == 2 {
-					var stringLenmapvalue uint64
+					var mapmsglen int
 					for shift := uint(0); ; shift += 7 {
 						if shift >= 64 {
 							return ErrIntOverflowEmail
@@ -1661,24 +12124,26 @@ func (m *ChunkedEmail) Unmarshal(dAtA []byte) error {
 						}
 						b := dAtA[iNdEx]
 						iNdEx++
-						stringLenmapvalue |= uint64(b&0x7F) << shift
+						mapmsglen |= int(b&0x7F) << shift
 						if b < 0x80 {
 							break
 						}
 					}
-					intStringLenmapvalue := int(stringLenmapvalue)
-					if intStringLenmapvalue < 0 {
+					if mapmsglen < 0 {
 						return ErrInvalidLengthEmail
 					}
-					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
-					if postStringIndexmapvalue < 0 {
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
 						return ErrInvalidLengthEmail
 					}
-					if postStringIndexmapvalue > l {
+					if postmsgIndex > l {
 						return io.ErrUnexpectedEOF
 					}
-					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
-					iNdEx = postStringIndexmapvalue
+					mapvalue = &Headers{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
 				} else {
 					iNdEx = entryPreIndex
 					skippy, err := skipEmail(dAtA[iNdEx:])
@@ -1694,7 +12159,7 @@ func (m *ChunkedEmail) Unmarshal(dAtA []byte) error {
 					iNdEx += skippy
 				}
 			}
-			m.Parts[mapkey] = mapvalue
+			m.Values[mapkey] = *mapvalue
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1720,7 +12185,7 @@ func (m *ChunkedEmail) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Email) Unmarshal(dAtA []byte) error {
+func (m *Headers) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1743,17 +12208,17 @@ func (m *Email) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Email: wiretype end group for non-group")
+			return fmt.Errorf("proto: Headers: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Email: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Headers: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -1763,28 +12228,80 @@ func (m *Email) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthEmail
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Headers.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Values = append(m.Values, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
 				return err
 			}
-			iNdEx = postIndex
-		case 2:
+			if skippy < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Values) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Values: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Values: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Subject", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field V", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1812,77 +12329,64 @@ func (m *Email) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Subject = string(dAtA[iNdEx:postIndex])
+			m.V = append(m.V, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Addresses", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowEmail
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			if msglen < 0 {
+			if skippy < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
+			if (iNdEx + skippy) < 0 {
 				return ErrInvalidLengthEmail
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Addresses.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Date", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowEmail
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthEmail
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthEmail
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Address) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
 			}
-			if postIndex > l {
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Date, dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			iNdEx = postIndex
-		case 5:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Address: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Address: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MessageID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1910,11 +12414,11 @@ func (m *Email) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.MessageID = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field InReplyTo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1942,11 +12446,11 @@ func (m *Email) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.InReplyTo = append(m.InReplyTo, string(dAtA[iNdEx:postIndex]))
+			m.Address = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 7:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field References", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Normalized", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1974,13 +12478,13 @@ func (m *Email) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.References = append(m.References, string(dAtA[iNdEx:postIndex]))
+			m.Normalized = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 8:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Resent", wireType)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Smtputf8", wireType)
 			}
-			var msglen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -1990,31 +12494,68 @@ func (m *Email) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			m.Smtputf8 = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
+			if (iNdEx + skippy) < 0 {
 				return ErrInvalidLengthEmail
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Resent == nil {
-				m.Resent = &Resent{}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchIndex) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
 			}
-			if err := m.Resent.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
 			}
-			iNdEx = postIndex
-		case 10:
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchIndex: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchIndex: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field HtmlBody", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Terms", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2042,13 +12583,13 @@ func (m *Email) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.HtmlBody = string(dAtA[iNdEx:postIndex])
+			m.Terms = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 11:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TextBody", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
 			}
-			var stringLen uint64
+			m.Version = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -2058,27 +12599,67 @@ func (m *Email) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Version |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEmail(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
+			if (iNdEx + skippy) < 0 {
 				return ErrInvalidLengthEmail
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TextBody = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 12:
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchPostings) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEmail
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchPostings: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchPostings: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Attachments", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Postings", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2105,16 +12686,16 @@ func (m *Email) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Attachments = append(m.Attachments, Attachment{})
-			if err := m.Attachments[len(m.Attachments)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Postings = append(m.Postings, Posting{})
+			if err := m.Postings[len(m.Postings)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 13:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field EmbeddedFiles", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Tree", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -2124,25 +12705,23 @@ func (m *Email) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthEmail
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.EmbeddedFiles = append(m.EmbeddedFiles, EmbeddedFile{})
-			if err := m.EmbeddedFiles[len(m.EmbeddedFiles)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Tree = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2168,7 +12747,7 @@ func (m *Email) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Attachment) Unmarshal(dAtA []byte) error {
+func (m *Posting) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2191,15 +12770,15 @@ func (m *Attachment) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Attachment: wiretype end group for non-group")
+			return fmt.Errorf("proto: Posting: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Attachment: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Posting: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FileName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2227,13 +12806,13 @@ func (m *Attachment) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.FileName = string(dAtA[iNdEx:postIndex])
+			m.Hash = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ContentType", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fields", wireType)
 			}
-			var stringLen uint64
+			m.Fields = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -2243,29 +12822,16 @@ func (m *Attachment) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Fields |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthEmail
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthEmail
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.ContentType = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DataHash", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
 			}
-			var stringLen uint64
+			m.Count = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -2275,24 +12841,87 @@ func (m *Attachment) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Count |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthEmail
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthEmail
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 4:
+			if wireType == 0 {
+				var v uint32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowEmail
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.FieldCounts = append(m.FieldCounts, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowEmail
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthEmail
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthEmail
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.FieldCounts) == 0 {
+					m.FieldCounts = make([]uint32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowEmail
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.FieldCounts = append(m.FieldCounts, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field FieldCounts", wireType)
 			}
-			m.DataHash = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipEmail(dAtA[iNdEx:])
@@ -2317,7 +12946,7 @@ func (m *Attachment) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *EmbeddedFile) Unmarshal(dAtA []byte) error {
+func (m *MetadataIndex) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2340,15 +12969,15 @@ func (m *EmbeddedFile) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: EmbeddedFile: wiretype end group for non-group")
+			return fmt.Errorf("proto: MetadataIndex: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: EmbeddedFile: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: MetadataIndex: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ContentId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Records", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2376,13 +13005,13 @@ func (m *EmbeddedFile) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ContentId = string(dAtA[iNdEx:postIndex])
+			m.Records = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ContentType", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
 			}
-			var stringLen uint64
+			m.Version = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -2392,27 +13021,14 @@ func (m *EmbeddedFile) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Version |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthEmail
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthEmail
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.ContentType = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DataHash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Dates", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2440,7 +13056,7 @@ func (m *EmbeddedFile) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.DataHash = string(dAtA[iNdEx:postIndex])
+			m.Dates = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2466,7 +13082,7 @@ func (m *EmbeddedFile) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Addresses) Unmarshal(dAtA []byte) error {
+func (m *MetadataRecord) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2489,17 +13105,17 @@ func (m *Addresses) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Addresses: wiretype end group for non-group")
+			return fmt.Errorf("proto: MetadataRecord: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Addresses: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: MetadataRecord: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -2509,33 +13125,29 @@ func (m *Addresses) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthEmail
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Sender == nil {
-				m.Sender = &Address{}
-			}
-			if err := m.Sender.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Hash = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FromDomains", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -2545,31 +13157,29 @@ func (m *Addresses) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthEmail
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.From = append(m.From, Address{})
-			if err := m.From[len(m.From)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.FromDomains = append(m.FromDomains, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ReplyTo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ToDomains", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -2579,31 +13189,29 @@ func (m *Addresses) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthEmail
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ReplyTo = append(m.ReplyTo, Address{})
-			if err := m.ReplyTo[len(m.ReplyTo)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.ToDomains = append(m.ToDomains, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field To", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CcDomains", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -2613,29 +13221,27 @@ func (m *Addresses) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthEmail
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.To = append(m.To, Address{})
-			if err := m.To[len(m.To)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.CcDomains = append(m.CcDomains, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Cc", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Date", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2662,16 +13268,53 @@ func (m *Addresses) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Cc = append(m.Cc, Address{})
-			if err := m.Cc[len(m.Cc)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Date, dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageSize", wireType)
+			}
+			m.MessageSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MessageSize |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attachments", wireType)
+			}
+			m.Attachments = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Attachments |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Bcc", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AttachmentTypes", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -2681,25 +13324,55 @@ func (m *Addresses) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthEmail
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Bcc = append(m.Bcc, Address{})
-			if err := m.Bcc[len(m.Bcc)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.AttachmentTypes = append(m.AttachmentTypes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
+			m.Headers = append(m.Headers, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2725,7 +13398,7 @@ func (m *Addresses) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Resent) Unmarshal(dAtA []byte) error {
+func (m *DateEntry) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2748,15 +13421,15 @@ func (m *Resent) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Resent: wiretype end group for non-group")
+			return fmt.Errorf("proto: DateEntry: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Resent: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DateEntry: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Addresses", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Date", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2783,46 +13456,13 @@ func (m *Resent) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Addresses.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Date, dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ResentDate", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowEmail
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthEmail
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthEmail
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.ResentDate, dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ResentMessageId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2848,68 +13488,15 @@ func (m *Resent) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthEmail
 			}
 			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.ResentMessageId = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipEmail(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthEmail
-			}
-			if (iNdEx + skippy) < 0 {
-				return ErrInvalidLengthEmail
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Header) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowEmail
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Header: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Header: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+				return io.ErrUnexpectedEOF
+			}
+			m.MessageID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -2919,120 +13506,23 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthEmail
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Values == nil {
-				m.Values = make(map[string]Headers)
-			}
-			var mapkey string
-			mapvalue := &Headers{}
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowEmail
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowEmail
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthEmail
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthEmail
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var mapmsglen int
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowEmail
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapmsglen |= int(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					if mapmsglen < 0 {
-						return ErrInvalidLengthEmail
-					}
-					postmsgIndex := iNdEx + mapmsglen
-					if postmsgIndex < 0 {
-						return ErrInvalidLengthEmail
-					}
-					if postmsgIndex > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = &Headers{}
-					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
-						return err
-					}
-					iNdEx = postmsgIndex
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipEmail(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthEmail
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
-				}
-			}
-			m.Values[mapkey] = *mapvalue
+			m.Hash = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -3058,7 +13548,7 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Headers) Unmarshal(dAtA []byte) error {
+func (m *TreeNode) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3081,17 +13571,36 @@ func (m *Headers) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Headers: wiretype end group for non-group")
+			return fmt.Errorf("proto: TreeNode: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Headers: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TreeNode: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Level", wireType)
+			}
+			m.Level = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Level |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Entries", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -3101,24 +13610,79 @@ func (m *Headers) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthEmail
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Values = append(m.Values, string(dAtA[iNdEx:postIndex]))
+			m.Entries = append(m.Entries, TreeEntry{})
+			if err := m.Entries[len(m.Entries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Children", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Children = append(m.Children, TreeChild{})
+			if err := m.Children[len(m.Children)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipEmail(dAtA[iNdEx:])
@@ -3143,7 +13707,7 @@ func (m *Headers) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Values) Unmarshal(dAtA []byte) error {
+func (m *TreeEntry) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3166,17 +13730,17 @@ func (m *Values) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Values: wiretype end group for non-group")
+			return fmt.Errorf("proto: TreeEntry: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Values: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TreeEntry: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field V", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -3186,23 +13750,59 @@ func (m *Values) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthEmail
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.V = append(m.V, string(dAtA[iNdEx:postIndex]))
+			m.Key = append(m.Key[:0], dAtA[iNdEx:postIndex]...)
+			if m.Key == nil {
+				m.Key = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthEmail
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEmail
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -3228,7 +13828,7 @@ func (m *Values) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Address) Unmarshal(dAtA []byte) error {
+func (m *TreeChild) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3251,15 +13851,15 @@ func (m *Address) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Address: wiretype end group for non-group")
+			return fmt.Errorf("proto: TreeChild: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Address: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TreeChild: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3287,13 +13887,13 @@ func (m *Address) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			m.Hash = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Last", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowEmail
@@ -3303,24 +13903,45 @@ func (m *Address) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthEmail
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthEmail
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Address = string(dAtA[iNdEx:postIndex])
+			m.Last = append(m.Last[:0], dAtA[iNdEx:postIndex]...)
+			if m.Last == nil {
+				m.Last = []byte{}
+			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEmail
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipEmail(dAtA[iNdEx:])
@@ -0,0 +1,183 @@
+package ipldeml
+
+import (
+	"regexp"
+	"strings"
+)
+
+// contains helpers for finding the quoted regions of a body, which replies
+// repeat from earlier messages in a thread
+
+// quoted regions smaller than this are left inline
+const minQuoteSize = 64
+
+var (
+	blockquoteRegex      = regexp.MustCompile(`(?i)<(/?)blockquote\b[^>]*>`)
+	originalMessageRegex = regexp.MustCompile(`^\s*-{2,}\s*(Original Message|Forwarded message)\s*-{2,}\s*$`)
+)
+
+// quoteSegment is a piece of a body, which is either inline or quoted
+type quoteSegment struct {
+	text   string
+	quoted bool
+	// set when the > quote markers have been removed from text
+	unquoted bool
+}
+
+// splitQuotes splits a body at its quoted regions. joining the text of the
+// segments, after requoting any unquoted segments, gives back the body
+func splitQuotes(body string, html bool) []quoteSegment {
+	var segments []quoteSegment
+	addInline := func(text string) {
+		if text == "" {
+			return
+		}
+		if n := len(segments); n > 0 && !segments[n-1].quoted {
+			segments[n-1].text += text
+			return
+		}
+		segments = append(segments, quoteSegment{text: text})
+	}
+	addQuoted := func(segment quoteSegment, raw string) {
+		if len(raw) < minQuoteSize {
+			addInline(raw)
+			return
+		}
+		segments = append(segments, segment)
+	}
+	if html {
+		splitBlockquotes(body, addInline, addQuoted)
+	} else {
+		splitQuotedLines(body, addInline, addQuoted)
+	}
+	return segments
+}
+
+// splitBlockquotes splits html at the outermost blockquote elements, keeping
+// the blockquote tags inline and quoting their contents
+func splitBlockquotes(body string, addInline func(string), addQuoted func(quoteSegment, string)) {
+	var depth, last, start int
+	for _, loc := range blockquoteRegex.FindAllStringSubmatchIndex(body, -1) {
+		closing := loc[3] > loc[2]
+		switch {
+		case !closing:
+			if depth == 0 {
+				addInline(body[last:loc[1]])
+				start, last = loc[1], loc[1]
+			}
+			depth++
+		case depth > 0:
+			depth--
+			if depth == 0 {
+				addQuoted(quoteSegment{text: body[start:loc[0]], quoted: true}, body[start:loc[0]])
+				last = loc[0]
+			}
+		}
+	}
+	addInline(body[last:])
+}
+
+// splitQuotedLines splits text at runs of lines starting with >, and at
+// markers introducing an unprefixed copy of an earlier message
+func splitQuotedLines(body string, addInline func(string), addQuoted func(quoteSegment, string)) {
+	lines := strings.SplitAfter(body, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, ">") {
+			j := i
+			for j < len(lines) && strings.HasPrefix(lines[j], ">") {
+				j++
+			}
+			region := strings.Join(lines[i:j], "")
+			// leave the final line ending inline so the quoted text
+			// matches the body it was quoted from
+			trimmed := strings.TrimSuffix(strings.TrimSuffix(region, "\n"), "\r")
+			segment := quoteSegment{text: trimmed, quoted: true}
+			if text, ok := unquoteLines(trimmed); ok {
+				segment.text, segment.unquoted = text, true
+			}
+			addQuoted(segment, trimmed)
+			addInline(region[len(trimmed):])
+			i = j - 1
+			continue
+		}
+		addInline(line)
+		if isQuoteMarker(lines, i) && !nextLineQuoted(lines, i+1) {
+			// everything after the marker is a copy of an earlier message
+			rest := strings.Join(lines[i+1:], "")
+			addQuoted(quoteSegment{text: rest, quoted: true}, rest)
+			return
+		}
+	}
+}
+
+// isQuoteMarker reports if line i introduces a copy of an earlier message,
+// such as an outlook original message separator or an "On ... wrote:" line
+func isQuoteMarker(lines []string, i int) bool {
+	line := strings.TrimSpace(lines[i])
+	if originalMessageRegex.MatchString(line) {
+		return true
+	}
+	if !strings.HasSuffix(line, "wrote:") {
+		return false
+	}
+	// the attribution line is often wrapped onto a second line
+	return strings.HasPrefix(line, "On ") || (i > 0 && strings.HasPrefix(lines[i-1], "On "))
+}
+
+// nextLineQuoted reports if the first non-blank line from i starts with >
+func nextLineQuoted(lines []string, i int) bool {
+	for ; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "" {
+			return strings.HasPrefix(lines[i], ">")
+		}
+	}
+	return false
+}
+
+// quoteLines adds > markers to every line the way mail clients do
+func quoteLines(text string) string {
+	var sb strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		content := strings.TrimRight(line, "\r\n")
+		switch {
+		case content == "":
+			sb.WriteString(">")
+		case strings.HasPrefix(content, ">"):
+			sb.WriteString(">")
+		default:
+			sb.WriteString("> ")
+		}
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
+
+// unquoteLines removes a level of > markers, returning false if quoteLines
+// would not give back the same text
+func unquoteLines(text string) (string, bool) {
+	var sb strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if !strings.HasPrefix(line, ">") {
+			return "", false
+		}
+		line = line[1:]
+		if strings.HasPrefix(line, " ") {
+			line = line[1:]
+		}
+		sb.WriteString(line)
+	}
+	unquoted := sb.String()
+	return unquoted, quoteLines(unquoted) == text
+}
+
+// hasQuotes reports if splitting body gave any quoted segments other than
+// the body itself, which happens when its quote markers can't be removed
+func hasQuotes(segments []quoteSegment, body string) bool {
+	for _, segment := range segments {
+		if segment.quoted && segment.text != body {
+			return true
+		}
+	}
+	return false
+}
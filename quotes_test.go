@@ -0,0 +1,82 @@
+package ipldeml
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// rebuild mirrors putBody and getBody without storing anything
+func rebuild(body string, html bool) string {
+	segments := splitQuotes(body, html)
+	if !hasQuotes(segments, body) {
+		return body
+	}
+	var sb strings.Builder
+	for _, segment := range segments {
+		if !segment.quoted {
+			sb.WriteString(segment.text)
+			continue
+		}
+		text := rebuild(segment.text, html)
+		if segment.unquoted {
+			text = quoteLines(text)
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
+
+func TestSplitQuotes(t *testing.T) {
+	parent := "hello there, this is the first message of the thread\nwith a second line\n\nand a third"
+	reply := "a reply\n\nOn Fri, Mar 27, 2020 at 1:35 AM Someone <\nsomeone@example.com> wrote:\n\n" + quoteLines(parent) + "\n"
+	segments := splitQuotes(reply, false)
+	if len(segments) != 3 || !segments[1].quoted || !segments[1].unquoted {
+		t.Fatal("bad segments", segments)
+	}
+	if segments[1].text != parent {
+		t.Fatal("quoted text does not match the parent")
+	}
+	outlook := "a reply\r\n\r\n-----Original Message-----\r\n" + parent
+	if segments := splitQuotes(outlook, false); len(segments) != 2 || segments[1].text != parent {
+		t.Fatal("bad outlook segments", segments)
+	}
+	html := `<div>reply</div><blockquote class="q"><div>` + parent + `</div><blockquote>nested</blockquote></blockquote><div>sig</div>`
+	segments = splitQuotes(html, true)
+	if len(segments) != 3 || segments[1].text != "<div>"+parent+"</div><blockquote>nested</blockquote>" {
+		t.Fatal("bad html segments", segments)
+	}
+	for _, body := range []string{reply, outlook, html, quoteLines(reply), ">not requotable " + parent + "\n>" + parent} {
+		if got := rebuild(body, body == html); got != body {
+			t.Fatalf("failed to rebuild %q, got %q", body, got)
+		}
+	}
+}
+
+func TestSplitQuotesSamples(t *testing.T) {
+	for _, file := range getSamples(t, "samples") {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := mail.ReadMessage(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts, err := readParts(msg.Header, msg.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, part := range parts {
+			if part.kind != partText && part.kind != partHTML {
+				continue
+			}
+			body := string(part.data)
+			if got := rebuild(body, part.kind == partHTML); got != body {
+				t.Fatalf("failed to rebuild body of %s", file)
+			}
+		}
+	}
+}
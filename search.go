@@ -0,0 +1,415 @@
+package ipldeml
+
+import (
+	"math/bits"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// contains the full text search index, an inverted index from the terms of
+// the subject, bodies and addresses of stored emails to the emails containing
+// them, kept in a tree so versions of the index share every node which the
+// emails added in between didn't touch
+
+// SearchField is a bit set of the fields of an email a term was found in
+type SearchField uint32
+
+const (
+	// SearchSubject is the subject
+	SearchSubject SearchField = 1 << iota
+	// SearchBody is the text body, and the text of the html body
+	SearchBody
+	// SearchFrom is the sender, from and reply-to addresses
+	SearchFrom
+	// SearchTo is the to, cc and bcc addresses
+	SearchTo
+	// SearchAll is every field
+	SearchAll = SearchSubject | SearchBody | SearchFrom | SearchTo
+)
+
+// searchFields are the field names accepted as query prefixes, such as "from:alice"
+var searchFields = map[string]SearchField{
+	"subject": SearchSubject,
+	"body":    SearchBody,
+	"from":    SearchFrom,
+	"to":      SearchTo,
+}
+
+const (
+	// terms longer than this are not indexed
+	maxSearchTermSize = 64
+	// terms found in more emails than this keep their postings in a tree of
+	// their own, so adding an email doesn't rewrite every posting of a
+	// frequent term
+	maxInlinePostings = 64
+)
+
+// SearchResult is an email matching a search, scored by the number of
+// times the terms of the search were found in the fields they were limited to
+type SearchResult struct {
+	Hash  string
+	Score uint64
+}
+
+// searchClause is a term of a query, and the fields it must be found in
+type searchClause struct {
+	term   string
+	fields SearchField
+}
+
+// IndexEmails adds the stored emails with the given hashes to the search
+// index with the given root, and returns the new root. an empty root starts
+// a new index
+func (c *Converter) IndexEmails(root string, hashes ...string) (string, error) {
+	index := new(pb.SearchIndex)
+	if root != "" {
+		var err error
+		if index, err = c.getSearchIndex(root); err != nil {
+			return "", err
+		}
+	}
+	postings := make(map[string][]pb.Posting)
+	for _, hash := range hashes {
+		email, err := c.GetEmail(hash)
+		if err != nil {
+			return "", err
+		}
+		for term, posting := range emailTerms(email) {
+			posting.Hash = hash
+			postings[term] = append(postings[term], posting)
+		}
+	}
+	terms, err := addSearchPostings(c, index.Terms, postings)
+	if err != nil {
+		return "", err
+	}
+	return c.putSearchIndex(&pb.SearchIndex{Terms: terms})
+}
+
+// Search returns the emails in the search index with the given root which
+// contain every term of the query, ordered by score. terms may be prefixed
+// with a field name, such as "subject:plan" or "from:alice@example.com", to
+// only match that field
+func (c *Converter) Search(root, query string) ([]SearchResult, error) {
+	clauses := parseSearchQuery(query)
+	if len(clauses) == 0 {
+		return nil, nil
+	}
+	index, err := c.getSearchIndex(root)
+	if err != nil {
+		return nil, err
+	}
+	return searchTree(c, index.Terms, clauses)
+}
+
+// parseSearchQuery returns the clauses of a query. words with a prefix
+// which isn't a field name, such as urls, are searched for whole
+func parseSearchQuery(query string) []searchClause {
+	var clauses []searchClause
+	for _, word := range strings.Fields(query) {
+		fields := SearchAll
+		if i := strings.IndexByte(word, ':'); i > 0 {
+			if field, ok := searchFields[strings.ToLower(word[:i])]; ok {
+				fields, word = field, word[i+1:]
+			}
+		}
+		terms := searchTerms(word)
+		// addresses are also indexed whole
+		if strings.Contains(word, "@") {
+			terms = []string{strings.ToLower(strings.Trim(word, "<>"))}
+		}
+		for _, term := range terms {
+			clauses = append(clauses, searchClause{term: term, fields: fields})
+		}
+	}
+	return clauses
+}
+
+// emailTerms returns the postings of every term in the email, without their hash
+func emailTerms(email *pb.Email) map[string]pb.Posting {
+	var (
+		postings = make(map[string]pb.Posting)
+		counts   = make(map[string][]uint32)
+	)
+	add := func(term string, field SearchField) {
+		posting := postings[term]
+		posting.Fields |= uint32(field)
+		posting.Count++
+		postings[term] = posting
+		if counts[term] == nil {
+			counts[term] = make([]uint32, bits.Len32(uint32(SearchAll)))
+		}
+		counts[term][bits.TrailingZeros32(uint32(field))]++
+	}
+	addText := func(text string, field SearchField) {
+		for _, term := range searchTerms(text) {
+			add(term, field)
+		}
+	}
+	addAddresses := func(field SearchField, lists ...[]pb.Address) {
+		for _, list := range lists {
+			for _, address := range list {
+				addText(address.Name, field)
+				addText(address.Address, field)
+				if address.Address != "" && len(address.Address) <= maxSearchTermSize {
+					add(strings.ToLower(address.Address), field)
+				}
+			}
+		}
+	}
+	addText(email.Subject, SearchSubject)
+	addText(email.TextBody, SearchBody)
+	addText(htmlText(email.HtmlBody), SearchBody)
+	addresses := email.Addresses
+	if addresses.Sender != nil {
+		addAddresses(SearchFrom, []pb.Address{*addresses.Sender})
+	}
+	addAddresses(SearchFrom, addresses.From, addresses.ReplyTo)
+	addAddresses(SearchTo, addresses.To, addresses.Cc, addresses.Bcc)
+	for term, posting := range postings {
+		if bits.OnesCount32(posting.Fields) < 2 {
+			continue
+		}
+		for _, count := range counts[term] {
+			if count > 0 {
+				posting.FieldCounts = append(posting.FieldCounts, count)
+			}
+		}
+		postings[term] = posting
+	}
+	return postings
+}
+
+// fieldCount returns the number of times the term of a posting was found in
+// any of the given fields
+func fieldCount(posting *pb.Posting, fields SearchField) uint64 {
+	if len(posting.FieldCounts) == 0 {
+		return uint64(posting.Count)
+	}
+	var count uint64
+	i := 0
+	for field := SearchSubject; field&SearchAll != 0; field <<= 1 {
+		if SearchField(posting.Fields)&field == 0 {
+			continue
+		}
+		if fields&field != 0 && i < len(posting.FieldCounts) {
+			count += uint64(posting.FieldCounts[i])
+		}
+		i++
+	}
+	return count
+}
+
+// searchTerms splits text into lower case terms at anything other than
+// letters and digits, leaving out single characters and overly long terms
+func searchTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := words[:0]
+	for _, word := range words {
+		if len([]rune(word)) > 1 && len(word) <= maxSearchTermSize {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// htmlText returns the text of an html body, leaving out scripts and styles
+func htmlText(body string) string {
+	var (
+		sb   strings.Builder
+		skip int
+		z    = html.NewTokenizer(strings.NewReader(body))
+	)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			// io.EOF at the end of the body, or a read error
+			return sb.String()
+		case html.StartTagToken:
+			if name, _ := z.TagName(); isHiddenElement(atom.Lookup(name)) {
+				skip++
+			}
+		case html.EndTagToken:
+			if name, _ := z.TagName(); isHiddenElement(atom.Lookup(name)) && skip > 0 {
+				skip--
+			}
+		case html.TextToken:
+			if skip == 0 {
+				sb.Write(z.Text())
+				sb.WriteByte(' ')
+			}
+		}
+	}
+}
+
+// isHiddenElement reports if the text of an element isn't displayed
+func isHiddenElement(a atom.Atom) bool {
+	return a == atom.Script || a == atom.Style || a == atom.Head
+}
+
+// searchTree returns the emails matching every clause in the tree of terms
+// with the given root, ordered by score
+func searchTree(store treeNodeStore, root string, clauses []searchClause) ([]SearchResult, error) {
+	var scores map[string]uint64
+	for i, clause := range clauses {
+		matched := make(map[string]uint64)
+		err := walkPostings(store, root, clause.term, func(posting pb.Posting) error {
+			if SearchField(posting.Fields)&clause.fields != 0 {
+				matched[posting.Hash] = fieldCount(&posting, clause.fields)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			scores = matched
+			continue
+		}
+		for hash := range scores {
+			if count, ok := matched[hash]; ok {
+				scores[hash] += count
+			} else {
+				delete(scores, hash)
+			}
+		}
+	}
+	results := make([]SearchResult, 0, len(scores))
+	for hash, score := range scores {
+		results = append(results, SearchResult{Hash: hash, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Hash < results[j].Hash
+	})
+	return results, nil
+}
+
+// addSearchPostings adds the postings of terms to the tree of terms with the
+// given root, and returns the new root
+func addSearchPostings(store treeNodeStore, root string, postings map[string][]pb.Posting) (string, error) {
+	entries := make([]pb.TreeEntry, 0, len(postings))
+	for term, list := range postings {
+		value, err := (&pb.SearchPostings{Postings: list}).Marshal()
+		if err != nil {
+			return "", err
+		}
+		entries = append(entries, pb.TreeEntry{Key: []byte(term), Value: value})
+	}
+	return putTreeEntries(store, root, entries, func(_, existing, value []byte) ([]byte, error) {
+		return mergeSearchPostings(store, existing, value)
+	})
+}
+
+// mergeSearchPostings adds the serialized postings in value to the existing
+// serialized postings of a term, moving them to a tree once there are too many
+func mergeSearchPostings(store treeNodeStore, existing, value []byte) ([]byte, error) {
+	postings, added := new(pb.SearchPostings), new(pb.SearchPostings)
+	if err := postings.Unmarshal(existing); err != nil {
+		return nil, err
+	}
+	if err := added.Unmarshal(value); err != nil {
+		return nil, err
+	}
+	added.Postings = mergePostings(nil, added.Postings)
+	if postings.Tree == "" {
+		postings.Postings = mergePostings(postings.Postings, added.Postings)
+		if len(postings.Postings) <= maxInlinePostings {
+			return postings.Marshal()
+		}
+		added.Postings, postings.Postings = postings.Postings, nil
+	}
+	entries := make([]pb.TreeEntry, 0, len(added.Postings))
+	for _, posting := range added.Postings {
+		key := []byte(posting.Hash)
+		posting.Hash = ""
+		data, err := posting.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, pb.TreeEntry{Key: key, Value: data})
+	}
+	var err error
+	if postings.Tree, err = putTreeEntries(store, postings.Tree, entries, nil); err != nil {
+		return nil, err
+	}
+	return postings.Marshal()
+}
+
+// walkPostings calls fn with every posting of a term in the tree of terms
+// with the given root
+func walkPostings(store treeNodeStore, root, term string, fn func(posting pb.Posting) error) error {
+	value, ok, err := getTreeValue(store, root, []byte(term))
+	if err != nil || !ok {
+		return err
+	}
+	postings := new(pb.SearchPostings)
+	if err := postings.Unmarshal(value); err != nil {
+		return err
+	}
+	for _, posting := range postings.Postings {
+		if err := fn(posting); err != nil {
+			return err
+		}
+	}
+	return walkTree(store, postings.Tree, func(entry pb.TreeEntry) error {
+		var posting pb.Posting
+		if err := posting.Unmarshal(entry.Value); err != nil {
+			return err
+		}
+		posting.Hash = string(entry.Key)
+		return fn(posting)
+	})
+}
+
+// mergePostings adds postings to the sorted existing postings, with later
+// postings replacing earlier ones for the same email
+func mergePostings(existing, postings []pb.Posting) []pb.Posting {
+	byHash := make(map[string]pb.Posting, len(existing)+len(postings))
+	for _, list := range [][]pb.Posting{existing, postings} {
+		for _, posting := range list {
+			byHash[posting.Hash] = posting
+		}
+	}
+	merged := make([]pb.Posting, 0, len(byHash))
+	for _, posting := range byHash {
+		merged = append(merged, posting)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Hash < merged[j].Hash })
+	return merged
+}
+
+// getSearchIndex returns the search index stored at hash
+func (c *Converter) getSearchIndex(hash string) (*pb.SearchIndex, error) {
+	data, err := c.getBytes(hash)
+	if err != nil {
+		return nil, err
+	}
+	index := new(pb.SearchIndex)
+	if err := index.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(index.Version); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// putSearchIndex stores a search index, returning its hash
+func (c *Converter) putSearchIndex(index *pb.SearchIndex) (string, error) {
+	index.Version = compatibleVersion
+	data, err := index.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return c.putBytes(data)
+}
@@ -0,0 +1,182 @@
+package ipldeml
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"Ship it on Friday!", []string{"ship", "it", "on", "friday"}},
+		{"a b c", []string{}},
+		{"alice@example.com", []string{"alice", "example", "com"}},
+		{"Grüße aus Köln, 2020", []string{"grüße", "aus", "köln", "2020"}},
+	}
+	for _, tt := range tests {
+		if got := searchTerms(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("searchTerms(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestHTMLText(t *testing.T) {
+	body := `<html><head><title>hidden</title><style>p { color: red }</style></head>` +
+		`<body><p>the <b>plan</b></p><script>var x = 1</script><p>friday</p></body></html>`
+	if got := searchTerms(htmlText(body)); !reflect.DeepEqual(got, []string{"the", "plan", "friday"}) {
+		t.Fatalf("htmlText() terms = %q", got)
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	got := parseSearchQuery("from:Alice@Example.com SUBJECT:the-plan friday http://example.com")
+	want := []searchClause{
+		{"alice@example.com", SearchFrom},
+		{"the", SearchSubject},
+		{"plan", SearchSubject},
+		{"friday", SearchAll},
+		{"http", SearchAll},
+		{"example", SearchAll},
+		{"com", SearchAll},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseSearchQuery() = %+v, want %+v", got, want)
+	}
+}
+
+func TestEmailTerms(t *testing.T) {
+	email := &pb.Email{
+		Subject:  "the plan",
+		TextBody: "plan for friday",
+		HtmlBody: "<p>plan</p>",
+		Addresses: pb.Addresses{
+			From: []pb.Address{{Name: "Alice", Address: "alice@example.com"}},
+			Cc:   []pb.Address{{Address: "Bob@example.com"}},
+		},
+	}
+	terms := emailTerms(email)
+	tests := []struct {
+		term        string
+		fields      SearchField
+		count       uint32
+		fieldCounts []uint32
+	}{
+		{"plan", SearchSubject | SearchBody, 3, []uint32{1, 2}},
+		{"friday", SearchBody, 1, nil},
+		{"alice", SearchFrom, 2, nil},
+		{"alice@example.com", SearchFrom, 1, nil},
+		{"bob@example.com", SearchTo, 1, nil},
+		{"example", SearchFrom | SearchTo, 2, []uint32{1, 1}},
+	}
+	for _, tt := range tests {
+		posting := terms[tt.term]
+		if SearchField(posting.Fields) != tt.fields || posting.Count != tt.count || !reflect.DeepEqual(posting.FieldCounts, tt.fieldCounts) {
+			t.Errorf("term %q = %+v, want fields %v count %v field counts %v", tt.term, posting, tt.fields, tt.count, tt.fieldCounts)
+		}
+	}
+}
+
+func TestFieldCount(t *testing.T) {
+	multiple := &pb.Posting{Fields: uint32(SearchSubject | SearchBody | SearchTo), Count: 6, FieldCounts: []uint32{1, 2, 3}}
+	single := &pb.Posting{Fields: uint32(SearchBody), Count: 4}
+	tests := []struct {
+		name    string
+		posting *pb.Posting
+		fields  SearchField
+		want    uint64
+	}{
+		{"all fields", multiple, SearchAll, 6},
+		{"subject", multiple, SearchSubject, 1},
+		{"body", multiple, SearchBody, 2},
+		{"to", multiple, SearchTo, 3},
+		{"subject and to", multiple, SearchSubject | SearchTo, 4},
+		{"single field", single, SearchBody, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fieldCount(tt.posting, tt.fields); got != tt.want {
+				t.Fatalf("fieldCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergePostings(t *testing.T) {
+	existing := []pb.Posting{{Hash: "a", Count: 1}, {Hash: "c", Count: 1}}
+	postings := []pb.Posting{{Hash: "b", Count: 2}, {Hash: "c", Count: 3}}
+	want := []pb.Posting{{Hash: "a", Count: 1}, {Hash: "b", Count: 2}, {Hash: "c", Count: 3}}
+	if got := mergePostings(existing, postings); !reflect.DeepEqual(got, want) {
+		t.Fatalf("mergePostings() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSearchTree(t *testing.T) {
+	store := memoryTreeStore{}
+	var root string
+	// every email has the term "common", which moves to a tree of its own,
+	// and every tenth email has "tenth" in its subject
+	for batch := 0; batch < 4; batch++ {
+		postings := make(map[string][]pb.Posting)
+		for i := batch * 50; i < (batch+1)*50; i++ {
+			hash := "hash-" + strconv.Itoa(i)
+			postings["common"] = append(postings["common"], pb.Posting{Hash: hash, Fields: uint32(SearchBody), Count: 1})
+			postings["term"+strconv.Itoa(i)] = []pb.Posting{{Hash: hash, Fields: uint32(SearchBody), Count: 1}}
+			if i%10 == 0 {
+				postings["tenth"] = append(postings["tenth"], pb.Posting{Hash: hash, Fields: uint32(SearchSubject), Count: 2})
+			}
+		}
+		var err error
+		if root, err = addSearchPostings(store, root, postings); err != nil {
+			t.Fatal(err)
+		}
+	}
+	value, _, err := getTreeValue(store, root, []byte("common"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	common := new(pb.SearchPostings)
+	if err := common.Unmarshal(value); err != nil {
+		t.Fatal(err)
+	}
+	if common.Tree == "" || len(common.Postings) != 0 {
+		t.Fatal("expected the postings of a frequent term to move to a tree")
+	}
+	tests := []struct {
+		clauses []searchClause
+		want    int
+	}{
+		{[]searchClause{{"common", SearchAll}}, 200},
+		{[]searchClause{{"tenth", SearchAll}}, 20},
+		{[]searchClause{{"tenth", SearchBody}}, 0},
+		{[]searchClause{{"common", SearchAll}, {"tenth", SearchSubject}}, 20},
+		{[]searchClause{{"common", SearchAll}, {"term42", SearchAll}}, 1},
+		{[]searchClause{{"missing", SearchAll}}, 0},
+	}
+	for _, tt := range tests {
+		results, err := searchTree(store, root, tt.clauses)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != tt.want {
+			t.Fatalf("searchTree(%v) = %v results, want %v", tt.clauses, len(results), tt.want)
+		}
+	}
+	// adding an email rewrites the paths to its terms, not the whole index
+	before := len(store)
+	postings := map[string][]pb.Posting{
+		"common":  {{Hash: "new", Fields: uint32(SearchBody), Count: 1}},
+		"termnew": {{Hash: "new", Fields: uint32(SearchBody), Count: 1}},
+	}
+	if _, err := addSearchPostings(store, root, postings); err != nil {
+		t.Fatal(err)
+	}
+	if written := len(store) - before; written > 8 {
+		t.Fatal("adding an email wrote", written, "nodes")
+	}
+}
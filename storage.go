@@ -0,0 +1,326 @@
+package ipldeml
+
+import (
+	"bytes"
+	"strings"
+
+	xpb "github.com/RTradeLtd/TxPB/v3/go"
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains the transformations applied to emails as they are stored and
+// retrieved, shared by the unixfs and chunked formats
+
+// encodeEmail stores the parts of the email which are kept in objects of
+// their own, and returns the serialized email object linking to them
+func (c *Converter) encodeEmail(email *pb.Email) ([]byte, error) {
+	summaryHash, err := c.putSummary(email)
+	if err != nil {
+		return nil, err
+	}
+	if c.opts.DeltaReplies {
+		if data, ok, err := c.encodeDelta(email, summaryHash); err != nil || ok {
+			return data, err
+		}
+	}
+	// copy so the callers email is left as is
+	stored := *email
+	stored.Version = compatibleVersion
+	// the summary is linked from the outermost object, so it is found first
+	var envelope *pb.Email
+	if c.opts.SplitEnvelope {
+		envelope = splitEnvelope(&stored)
+		envelope.SummaryHash = summaryHash
+	} else {
+		stored.SummaryHash = summaryHash
+	}
+	if c.opts.CompactHeaders {
+		compactHeaders(&stored)
+	}
+	if c.opts.ShareHeaders {
+		if err := c.putHeaderTemplate(&stored); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.splitBodies(&stored); err != nil {
+		return nil, err
+	}
+	data, err := stored.Marshal()
+	if err != nil || envelope == nil {
+		return data, err
+	}
+	if envelope.CoreHash, err = c.putBytes(data); err != nil {
+		return nil, err
+	}
+	return envelope.Marshal()
+}
+
+// decodeEmail parses a serialized email object, restoring the parts kept
+// in objects of their own so the result matches the output of Convert
+func (c *Converter) decodeEmail(data []byte) (*pb.Email, error) {
+	email := new(pb.Email)
+	if err := email.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	if err := upgradeEmail(email); err != nil {
+		return nil, err
+	}
+	// summaries are only read when listing emails
+	email.SummaryHash = ""
+	if email.Delta != nil {
+		return c.decodeDelta(email.Delta)
+	}
+	if email.CoreHash != "" {
+		return c.joinEnvelope(email)
+	}
+	if err := c.joinBodies(email); err != nil {
+		return nil, err
+	}
+	if err := c.joinHeaderTemplate(email); err != nil {
+		return nil, err
+	}
+	if err := expandHeaders(email); err != nil {
+		return nil, err
+	}
+	normalizeEmail(email)
+	return email, nil
+}
+
+// getStoredEmail returns an email object as it is stored, without restoring
+// the parts kept in objects of their own
+func (c *Converter) getStoredEmail(hash string) (*pb.Email, error) {
+	data, err := c.getBytes(hash)
+	if err != nil {
+		return nil, err
+	}
+	email := new(pb.Email)
+	if err := email.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return email, nil
+}
+
+// getStoredCore is like getStoredEmail, but returns the core of delivery
+// envelopes and rebuilds deltas, so the fields of the message itself are set
+func (c *Converter) getStoredCore(hash string) (*pb.Email, error) {
+	email, err := c.getStoredEmail(hash)
+	switch {
+	case err != nil:
+		return nil, err
+	case email.Delta != nil:
+		return c.decodeDelta(email.Delta)
+	case email.CoreHash != "":
+		return c.getStoredCore(email.CoreHash)
+	}
+	return email, nil
+}
+
+// normalizeEmail brings a decoded email into the form returned by Convert
+func normalizeEmail(email *pb.Email) {
+	// normalize time values
+	email.Date = email.Date.UTC()
+	for i := range email.ResentBlocks {
+		email.ResentBlocks[i].ResentDate = email.ResentBlocks[i].ResentDate.UTC()
+	}
+	canonicalizeEmail(email)
+}
+
+// splitBodies stores the text and html bodies as objects of their own, so
+// identical bodies are shared between emails whose headers differ
+func (c *Converter) splitBodies(email *pb.Email) error {
+	var err error
+	if email.TextBody != "" {
+		email.TextBodyHash, email.TextSegmentsHash, err = c.putBody(email.TextBody, false)
+		if err != nil {
+			return err
+		}
+		email.TextBody = ""
+	}
+	if email.HtmlBody != "" {
+		email.HtmlBodyHash, email.HtmlSegmentsHash, err = c.putBody(email.HtmlBody, true)
+		if err != nil {
+			return err
+		}
+		email.HtmlBody = ""
+	}
+	return nil
+}
+
+// joinBodies is the reverse of splitBodies
+func (c *Converter) joinBodies(email *pb.Email) error {
+	var err error
+	if email.TextBodyHash != "" || email.TextSegmentsHash != "" {
+		email.TextBody, err = c.getBody(email.TextBodyHash, email.TextSegmentsHash)
+		if err != nil {
+			return err
+		}
+		email.TextBodyHash, email.TextSegmentsHash = "", ""
+	}
+	if email.HtmlBodyHash != "" || email.HtmlSegmentsHash != "" {
+		email.HtmlBody, err = c.getBody(email.HtmlBodyHash, email.HtmlSegmentsHash)
+		if err != nil {
+			return err
+		}
+		email.HtmlBodyHash, email.HtmlSegmentsHash = "", ""
+	}
+	return nil
+}
+
+// putBody stores a body, returning the hash of the unixfs object holding it.
+// when quote deduplication or html chunking is enabled, the hash of a Segments
+// object is returned instead. quoted regions are stored the same way as a body
+// so a quoted copy of a message shares its objects with the original
+func (c *Converter) putBody(body string, html bool) (hash, segmentsHash string, err error) {
+	var (
+		quoted []quoteSegment
+		chunk  = html && c.opts.ChunkHTML
+	)
+	if c.opts.DedupeQuotes {
+		quoted = splitQuotes(body, html)
+	}
+	if !hasQuotes(quoted, body) {
+		if !chunk {
+			hash, err = c.putBytes([]byte(body))
+			return hash, "", err
+		}
+		quoted = []quoteSegment{{text: body}}
+	}
+	segments := new(pb.Segments)
+	for _, segment := range quoted {
+		if segment.quoted {
+			stored := pb.Segment{Unquoted: segment.unquoted}
+			stored.QuotedHash, stored.QuotedSegmentsHash, err = c.putBody(segment.text, html)
+			if err != nil {
+				return "", "", err
+			}
+			segments.Segments = append(segments.Segments, stored)
+			continue
+		}
+		if !chunk {
+			segments.Segments = append(segments.Segments, pb.Segment{Data: segment.text})
+			continue
+		}
+		for _, block := range SplitHTMLBlocks(segment.text) {
+			if !IsStoredHTMLBlock(block) {
+				segments.Segments = append(segments.Segments, pb.Segment{Data: block})
+				continue
+			}
+			blockHash, err := c.putBytes([]byte(block))
+			if err != nil {
+				return "", "", err
+			}
+			segments.Segments = append(segments.Segments, pb.Segment{DataHash: blockHash})
+		}
+	}
+	data, err := segments.Marshal()
+	if err != nil {
+		return "", "", err
+	}
+	segmentsHash, err = c.putBytes(data)
+	return "", segmentsHash, err
+}
+
+// getBody is the reverse of putBody
+func (c *Converter) getBody(hash, segmentsHash string) (string, error) {
+	if hash != "" {
+		data, err := c.getBytes(hash)
+		return string(data), err
+	}
+	segments, err := c.getSegments(segmentsHash)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, segment := range segments.Segments {
+		switch {
+		case segment.DataHash != "":
+			data, err := c.getBytes(segment.DataHash)
+			if err != nil {
+				return "", err
+			}
+			sb.Write(data)
+		case segment.QuotedHash != "" || segment.QuotedSegmentsHash != "":
+			text, err := c.getBody(segment.QuotedHash, segment.QuotedSegmentsHash)
+			if err != nil {
+				return "", err
+			}
+			if segment.Unquoted {
+				text = quoteLines(text)
+			}
+			sb.WriteString(text)
+		default:
+			sb.WriteString(segment.Data)
+		}
+	}
+	return sb.String(), nil
+}
+
+func (c *Converter) getSegments(hash string) (*pb.Segments, error) {
+	data, err := c.getBytes(hash)
+	if err != nil {
+		return nil, err
+	}
+	segments := new(pb.Segments)
+	if err := segments.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return segments, nil
+}
+
+// segmentHashes returns the hashes of a Segments object and every object it
+// links to which are not already in seen
+func (c *Converter) segmentHashes(hash string, seen map[string]bool) ([]string, error) {
+	if hash == "" || seen[hash] {
+		return nil, nil
+	}
+	seen[hash] = true
+	hashes := []string{hash}
+	segments, err := c.getSegments(hash)
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range segments.Segments {
+		for _, hash := range []string{segment.QuotedHash, segment.DataHash} {
+			if hash != "" && !seen[hash] {
+				seen[hash] = true
+				hashes = append(hashes, hash)
+			}
+		}
+		inner, err := c.segmentHashes(segment.QuotedSegmentsHash, seen)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, inner...)
+	}
+	return hashes, nil
+}
+
+// putBytes stores data as a unixfs object
+func (c *Converter) putBytes(data []byte) (string, error) {
+	// file size 0 == no progress eports
+	resp, err := c.xclient.UploadFile(c.ctx, bytes.NewReader(data), 0, nil, false)
+	if err != nil {
+		return "", err
+	}
+	return resp.GetHash(), nil
+}
+
+// putFile stores an attachment or embedded file of an email being converted,
+// leaving its hash empty when the converter has no client
+func (c *Converter) putFile(data []byte) (string, error) {
+	if c.xclient == nil {
+		return "", nil
+	}
+	return c.putBytes(data)
+}
+
+// getBytes returns the contents of a unixfs object
+func (c *Converter) getBytes(hash string) ([]byte, error) {
+	resp, err := c.xclient.DownloadFile(c.ctx, &xpb.DownloadRequest{
+		Hash: hash,
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Bytes(), nil
+}
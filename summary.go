@@ -0,0 +1,135 @@
+package ipldeml
+
+import (
+	"strings"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains summaries, small objects linked from every stored email holding
+// what is needed to list it, so thousands of emails can be listed without
+// fetching their bodies and attachments
+
+// snippets are cut to this many characters
+const maxSnippetSize = 200
+
+// ListSummaries returns the summaries of the stored emails with the given
+// hashes, in the same order. emails stored before summaries existed are
+// fetched whole to summarize them
+func (c *Converter) ListSummaries(hashes ...string) ([]pb.Summary, error) {
+	return c.listSummaries(hashes, c.getStoredEmail, c.GetEmail)
+}
+
+// ListSummariesChunked is like ListSummaries, but for emails in the chunked format
+func (c *Converter) ListSummariesChunked(hashes ...string) ([]pb.Summary, error) {
+	return c.listSummaries(hashes, func(hash string) (*pb.Email, error) {
+		data, err := c.getChunkedData(hash)
+		if err != nil {
+			return nil, err
+		}
+		email := new(pb.Email)
+		if err := email.Unmarshal(data); err != nil {
+			return nil, err
+		}
+		return email, nil
+	}, c.GetEmailChunked)
+}
+
+// listSummaries returns the summaries of emails, reading the stored email
+// objects with getStored, and whole emails without a summary with get
+func (c *Converter) listSummaries(hashes []string, getStored, get func(hash string) (*pb.Email, error)) ([]pb.Summary, error) {
+	summaries := make([]pb.Summary, 0, len(hashes))
+	for _, hash := range hashes {
+		stored, err := getStored(hash)
+		if err != nil {
+			return nil, err
+		}
+		if stored.SummaryHash == "" {
+			email, err := get(hash)
+			if err != nil {
+				return nil, err
+			}
+			summaries = append(summaries, emailSummary(email))
+			continue
+		}
+		summary, err := c.getSummary(stored.SummaryHash)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, *summary)
+	}
+	return summaries, nil
+}
+
+// emailSummary returns the summary of an email
+func emailSummary(email *pb.Email) pb.Summary {
+	return pb.Summary{
+		Subject:     email.Subject,
+		From:        email.Addresses.From,
+		To:          email.Addresses.To,
+		Date:        email.Date,
+		MessageSize: emailSize(email),
+		Attachments: uint32(len(email.Attachments)),
+		Snippet:     emailSnippet(email),
+	}
+}
+
+// emailSize returns the size of the bodies, attachments and embedded files of an email
+func emailSize(email *pb.Email) uint64 {
+	size := uint64(len(email.TextBody) + len(email.HtmlBody))
+	for _, attach := range email.Attachments {
+		size += attach.Metadata.FileSize
+	}
+	for _, embed := range email.EmbeddedFiles {
+		size += embed.Metadata.FileSize
+	}
+	return size
+}
+
+// emailSnippet returns the start of the text body without quoted lines, or
+// of the text of the html body, with whitespace collapsed
+func emailSnippet(email *pb.Email) string {
+	text := email.TextBody
+	if text == "" {
+		text = htmlText(email.HtmlBody)
+	}
+	var words []string
+	for _, line := range strings.Split(text, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), ">") {
+			words = append(words, strings.Fields(line)...)
+		}
+	}
+	snippet := []rune(strings.Join(words, " "))
+	if len(snippet) > maxSnippetSize {
+		snippet = snippet[:maxSnippetSize]
+	}
+	return string(snippet)
+}
+
+// putSummary stores the summary of an email, returning its hash
+func (c *Converter) putSummary(email *pb.Email) (string, error) {
+	summary := emailSummary(email)
+	summary.Version = compatibleVersion
+	data, err := summary.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return c.putBytes(data)
+}
+
+// getSummary returns the summary stored at hash
+func (c *Converter) getSummary(hash string) (*pb.Summary, error) {
+	data, err := c.getBytes(hash)
+	if err != nil {
+		return nil, err
+	}
+	summary := new(pb.Summary)
+	if err := summary.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(summary.Version); err != nil {
+		return nil, err
+	}
+	summary.Date = summary.Date.UTC()
+	return summary, nil
+}
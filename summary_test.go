@@ -0,0 +1,49 @@
+package ipldeml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+func TestEmailSnippet(t *testing.T) {
+	tests := []struct {
+		name  string
+		email *pb.Email
+		want  string
+	}{
+		{"empty", &pb.Email{}, ""},
+		{"text", &pb.Email{TextBody: "sounds  good\r\n\r\nsee you\tfriday\r\n"}, "sounds good see you friday"},
+		{"quoted", &pb.Email{TextBody: "sounds good\r\n\r\n> the plan\r\n  > is friday\r\n"}, "sounds good"},
+		{"html", &pb.Email{HtmlBody: "<style>p {}</style><p>sounds <b>good</b></p>"}, "sounds good"},
+		{"text over html", &pb.Email{TextBody: "text", HtmlBody: "<p>html</p>"}, "text"},
+		{"long", &pb.Email{TextBody: strings.Repeat("é", 300)}, strings.Repeat("é", maxSnippetSize)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := emailSnippet(tt.email); got != tt.want {
+				t.Fatalf("emailSnippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailSummary(t *testing.T) {
+	email := &pb.Email{
+		Subject:   "the plan",
+		Addresses: pb.Addresses{From: []pb.Address{{Address: "alice@example.com"}}},
+		TextBody:  "friday",
+		Attachments: []pb.Attachment{
+			{Metadata: pb.FileMetadata{FileSize: 100}},
+		},
+		EmbeddedFiles: []pb.EmbeddedFile{
+			{Metadata: pb.FileMetadata{FileSize: 10}},
+		},
+	}
+	summary := emailSummary(email)
+	if summary.Subject != "the plan" || len(summary.From) != 1 || summary.MessageSize != 116 ||
+		summary.Attachments != 1 || summary.Snippet != "friday" {
+		t.Fatalf("emailSummary() = %+v", summary)
+	}
+}
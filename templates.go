@@ -0,0 +1,162 @@
+package ipldeml
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains helpers for moving the headers repeated by many emails, such as
+// those added by the same mail system, into shared header template objects
+
+const (
+	// templates no larger than this are left inline, as they cost no more
+	// than the link that would replace them
+	minHeaderTemplateSize = 48
+	// header values found in fewer emails than this are left on the email
+	// when sharing headers by their counts
+	minHeaderRepeats = 2
+)
+
+// messageHeaders are the headers which differ between messages, and are
+// left out of header templates along with the trace headers when no header
+// counts are given
+var messageHeaders = map[string]bool{
+	"Bcc": true, "Cc": true, "Content-Type": true,
+	"Date": true, "Dkim-Signature": true, "From": true,
+	"In-Reply-To": true, "Message-Id": true, "References": true,
+	"Reply-To": true, "Sender": true, "Subject": true,
+	"Thread-Index": true, "Thread-Topic": true, "To": true,
+	"X-Google-Dkim-Signature": true,
+}
+
+// HeaderCounts counts the emails each header value was found in, so header
+// templates only hold the headers which repeat across a corpus
+type HeaderCounts map[string]uint32
+
+// Add counts the headers of an email
+func (h HeaderCounts) Add(header pb.Header) {
+	for name, values := range header.Values {
+		h[headerCountKey(name, values.Values)]++
+	}
+}
+
+// repeated reports if a header value was found in enough emails to share it
+func (h HeaderCounts) repeated(name string, values []string) bool {
+	return h[headerCountKey(name, values)] >= minHeaderRepeats
+}
+
+// headerCountKey returns the key counting a header value
+func headerCountKey(name string, values []string) string {
+	return name + "\x00" + strings.Join(values, "\x00")
+}
+
+// CountHeaders counts the header values of the converted emails in dir, so
+// Opts.HeaderCounts can share the headers which repeat between them
+func CountHeaders(dir string) (HeaderCounts, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		converter = NewConverter(context.Background(), nil)
+		counts    = make(HeaderCounts)
+	)
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		email, err := converter.Convert(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		counts.Add(email.Headers)
+	}
+	return counts, nil
+}
+
+// SplitHeaderTemplate splits headers into a template of the headers repeated
+// between emails, and the headers which are specific to this email. with
+// counts, the template holds the header values found in more than one email
+// of the corpus, otherwise the headers which usually differ between messages
+// are left out of it. the template is nil when it is too small to be worth
+// storing on its own
+func SplitHeaderTemplate(header pb.Header, counts HeaderCounts) (*pb.HeaderTemplate, pb.Header) {
+	var (
+		template = new(pb.HeaderTemplate)
+		rest     = pb.Header{Values: make(map[string]pb.Headers)}
+	)
+	for name, values := range header.Values {
+		shared := !messageHeaders[name] && !isTraceHeader(name)
+		if counts != nil {
+			shared = counts.repeated(name, values.Values)
+		}
+		if !shared {
+			rest.Values[name] = values
+			continue
+		}
+		template.Headers = append(template.Headers, pb.TemplateHeader{
+			Name:   name,
+			Values: values.Values,
+		})
+	}
+	if template.Size() <= minHeaderTemplateSize {
+		return nil, header
+	}
+	sort.Slice(template.Headers, func(i, j int) bool {
+		return template.Headers[i].Name < template.Headers[j].Name
+	})
+	return template, rest
+}
+
+// putHeaderTemplate stores the template headers of the email as an object
+// of their own, removing them from the headers map
+func (c *Converter) putHeaderTemplate(email *pb.Email) error {
+	template, rest := SplitHeaderTemplate(email.Headers, c.opts.HeaderCounts)
+	if template == nil {
+		return nil
+	}
+	data, err := template.Marshal()
+	if err != nil {
+		return err
+	}
+	hash, err := c.putBytes(data)
+	if err != nil {
+		return err
+	}
+	email.Headers = rest
+	email.HeaderTemplateHash = hash
+	return nil
+}
+
+// joinHeaderTemplate is the reverse of putHeaderTemplate
+func (c *Converter) joinHeaderTemplate(email *pb.Email) error {
+	if email.HeaderTemplateHash == "" {
+		return nil
+	}
+	data, err := c.getBytes(email.HeaderTemplateHash)
+	if err != nil {
+		return err
+	}
+	template := new(pb.HeaderTemplate)
+	if err := template.Unmarshal(data); err != nil {
+		return err
+	}
+	if email.Headers.Values == nil {
+		email.Headers.Values = make(map[string]pb.Headers, len(template.Headers))
+	}
+	for _, header := range template.Headers {
+		email.Headers.Values[header.Name] = pb.Headers{Values: header.Values}
+	}
+	email.HeaderTemplateHash = ""
+	return nil
+}
@@ -0,0 +1,94 @@
+package ipldeml
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+func TestSplitHeaderTemplate(t *testing.T) {
+	newHeader := func(to string) pb.Header {
+		return pb.Header{Values: map[string]pb.Headers{
+			"To":               {Values: []string{to}},
+			"Message-Id":       {Values: []string{"<" + to + ">"}},
+			"Mime-Version":     {Values: []string{"1.0"}},
+			"X-Mailer":         {Values: []string{"newsletter mailer 1.0"}},
+			"List-Id":          {Values: []string{"weekly newsletter <weekly.example.com>"}},
+			"List-Unsubscribe": {Values: []string{"<https://example.com/unsubscribe>"}},
+			"X-Feedback-Id":    {Values: []string{strings.Repeat("feedback", 8)}},
+		}}
+	}
+	var templates [][]byte
+	for _, to := range []string{"alice@example.com", "bob@example.com"} {
+		header := newHeader(to)
+		template, rest := SplitHeaderTemplate(header, nil)
+		if template == nil {
+			t.Fatal("no template")
+		}
+		if len(rest.Values) != 2 || len(template.Headers)+len(rest.Values) != len(header.Values) {
+			t.Fatal("bad split", template, rest)
+		}
+		for _, h := range template.Headers {
+			if _, ok := rest.Values[h.Name]; ok {
+				t.Fatal("header in both the template and the rest", h.Name)
+			}
+		}
+		data, err := template.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		templates = append(templates, data)
+	}
+	if !bytes.Equal(templates[0], templates[1]) {
+		t.Fatal("templates differ")
+	}
+	small := pb.Header{Values: map[string]pb.Headers{
+		"To":           {Values: []string{"alice@example.com"}},
+		"Mime-Version": {Values: []string{"1.0"}},
+	}}
+	if template, rest := SplitHeaderTemplate(small, nil); template != nil || len(rest.Values) != 2 {
+		t.Fatal("small template was split")
+	}
+}
+
+func TestSplitHeaderTemplateCounts(t *testing.T) {
+	newHeader := func(to string) pb.Header {
+		return pb.Header{Values: map[string]pb.Headers{
+			"To":            {Values: []string{to}},
+			"Date":          {Values: []string{"Mon, 2 Mar 2020 09:00:00 +0000"}},
+			"Mime-Version":  {Values: []string{"1.0"}},
+			"X-Mailer":      {Values: []string{"newsletter mailer 1.0"}},
+			"X-Campaign-Id": {Values: []string{"campaign for " + to}},
+		}}
+	}
+	counts := make(HeaderCounts)
+	for _, to := range []string{"alice@example.com", "bob@example.com", "carol@example.com"} {
+		counts.Add(newHeader(to))
+	}
+	// only the values repeated between emails are shared, so the values
+	// specific to each email don't stop the template from being shared
+	var templates [][]byte
+	for _, to := range []string{"alice@example.com", "bob@example.com"} {
+		template, rest := SplitHeaderTemplate(newHeader(to), counts)
+		if template == nil {
+			t.Fatal("no template")
+		}
+		if len(template.Headers) != 3 || len(rest.Values) != 2 {
+			t.Fatal("bad split", template, rest)
+		}
+		data, err := template.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		templates = append(templates, data)
+	}
+	if !bytes.Equal(templates[0], templates[1]) {
+		t.Fatal("templates differ")
+	}
+	// values only found once are never shared
+	if template, _ := SplitHeaderTemplate(newHeader("dave@example.com"), HeaderCounts{}); template != nil {
+		t.Fatal("unrepeated headers were shared")
+	}
+}
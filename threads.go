@@ -0,0 +1,351 @@
+package ipldeml
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains message threading, using the algorithm described by jamie
+// zawinski at https://www.jwz.org/doc/threading.html to build conversations
+// from the references of each email, falling back to their subjects
+
+// matches the reply and forward prefixes of a subject, such as "Re: " or "Fwd[2]: "
+var subjectPrefixRegex = regexp.MustCompile(`(?i)^\s*(re|fw|fwd|aw|sv|antw)(\[\d+\])?\s*:\s*`)
+
+// threadMessage is a stored email being threaded
+type threadMessage struct {
+	hash  string
+	email *pb.Email
+}
+
+// threadContainer is a node of a thread while threading, holding either a
+// message or a placeholder for a referenced message which is not stored
+type threadContainer struct {
+	messageID string
+	message   *threadMessage
+	parent    *threadContainer
+	children  []*threadContainer
+}
+
+// ThreadEmails threads the stored emails with the given hashes into
+// conversations, storing each conversation as a Thread object and returning
+// their hashes, ordered by the date of their first message
+func (c *Converter) ThreadEmails(hashes ...string) ([]string, error) {
+	messages := make([]threadMessage, 0, len(hashes))
+	for _, hash := range hashes {
+		// the threading fields are always stored on the email itself
+		email, err := c.getStoredCore(hash)
+		if err != nil {
+			return nil, err
+		}
+		if err := upgradeEmail(email); err != nil {
+			return nil, err
+		}
+		messages = append(messages, threadMessage{hash: hash, email: email})
+	}
+	var threadHashes []string
+	for _, thread := range buildThreads(messages) {
+		thread.Version = compatibleVersion
+		data, err := thread.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		hash, err := c.putBytes(data)
+		if err != nil {
+			return nil, err
+		}
+		threadHashes = append(threadHashes, hash)
+	}
+	return threadHashes, nil
+}
+
+// GetThread returns the thread stored at hash
+func (c *Converter) GetThread(hash string) (*pb.Thread, error) {
+	data, err := c.getBytes(hash)
+	if err != nil {
+		return nil, err
+	}
+	thread := new(pb.Thread)
+	if err := thread.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(thread.Version); err != nil {
+		return nil, err
+	}
+	return thread, nil
+}
+
+// buildThreads threads messages into conversations, ordered by date
+func buildThreads(messages []threadMessage) []pb.Thread {
+	// thread in a fixed order so the same messages give the same threads
+	sorted := append([]threadMessage(nil), messages...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return lessThreadMessage(&sorted[i], &sorted[j])
+	})
+	roots := groupBySubject(pruneContainers(linkContainers(sorted), true))
+	sortContainers(roots)
+	threads := make([]pb.Thread, len(roots))
+	for i, root := range roots {
+		subject, _ := containerSubject(root)
+		threads[i] = pb.Thread{
+			Subject: subject,
+			Root:    toThreadNode(root),
+			Count:   countMessages(root),
+		}
+	}
+	return threads
+}
+
+// linkContainers links the messages to the messages they reference, returning
+// the containers which have no parent
+func linkContainers(messages []threadMessage) []*threadContainer {
+	var (
+		ids        = make(map[string]*threadContainer)
+		containers []*threadContainer
+	)
+	get := func(id string) *threadContainer {
+		container, ok := ids[id]
+		if !ok {
+			container = &threadContainer{messageID: id}
+			ids[id] = container
+			containers = append(containers, container)
+		}
+		return container
+	}
+	for i := range messages {
+		message := &messages[i]
+		id := message.email.MessageID
+		if id == "" || (ids[id] != nil && ids[id].message != nil) {
+			// messages without an id, or with the id of another message,
+			// are identified by their hash instead
+			id = "hash:" + message.hash
+		}
+		container := get(id)
+		container.message = message
+		refs := message.email.References
+		if inReplyTo := message.email.InReplyTo; len(inReplyTo) > 0 &&
+			(len(refs) == 0 || refs[len(refs)-1] != inReplyTo[0]) {
+			refs = append(refs[:len(refs):len(refs)], inReplyTo[0])
+		}
+		// link each reference to the one before it, leaving existing links
+		var parent *threadContainer
+		for _, ref := range refs {
+			refContainer := get(ref)
+			if parent != nil && refContainer.parent == nil && !wouldLoop(parent, refContainer) {
+				setParent(refContainer, parent)
+			}
+			parent = refContainer
+		}
+		// the message always belongs under its last reference
+		if parent != nil && wouldLoop(parent, container) {
+			parent = nil
+		}
+		setParent(container, parent)
+	}
+	var roots []*threadContainer
+	for _, container := range containers {
+		if container.parent == nil {
+			roots = append(roots, container)
+		}
+	}
+	return roots
+}
+
+// wouldLoop reports if making child a child of parent would create a loop
+func wouldLoop(parent, child *threadContainer) bool {
+	for c := parent; c != nil; c = c.parent {
+		if c == child {
+			return true
+		}
+	}
+	return false
+}
+
+// setParent moves child from its current parent to parent, which may be nil
+func setParent(child, parent *threadContainer) {
+	if old := child.parent; old != nil {
+		for i, c := range old.children {
+			if c == child {
+				old.children = append(old.children[:i:i], old.children[i+1:]...)
+				break
+			}
+		}
+	}
+	child.parent = parent
+	if parent != nil {
+		parent.children = append(parent.children, child)
+	}
+}
+
+// pruneContainers removes placeholders without children, and replaces
+// placeholders with their children, except at the root where they are kept
+// to group several children
+func pruneContainers(containers []*threadContainer, root bool) []*threadContainer {
+	var pruned []*threadContainer
+	for _, container := range containers {
+		container.children = pruneContainers(container.children, false)
+		for _, child := range container.children {
+			child.parent = container
+		}
+		if container.message == nil {
+			if len(container.children) == 0 {
+				continue
+			}
+			if !root || len(container.children) == 1 {
+				for _, child := range container.children {
+					child.parent = container.parent
+				}
+				pruned = append(pruned, container.children...)
+				continue
+			}
+		}
+		pruned = append(pruned, container)
+	}
+	return pruned
+}
+
+// groupBySubject merges root containers with the same subject, for messages
+// whose references were lost
+func groupBySubject(roots []*threadContainer) []*threadContainer {
+	subjects := make(map[string]*threadContainer)
+	for _, root := range roots {
+		subject, reply := containerSubject(root)
+		if subject == "" {
+			continue
+		}
+		// prefer placeholders, then messages which are not replies
+		old, ok := subjects[subject]
+		if !ok || (root.message == nil && old.message != nil) ||
+			(old.message != nil && root.message != nil && isReply(old) && !reply) {
+			subjects[subject] = root
+		}
+	}
+	var grouped []*threadContainer
+	for _, root := range roots {
+		subject, reply := containerSubject(root)
+		target := subjects[subject]
+		if subject == "" || target == root {
+			grouped = append(grouped, root)
+			continue
+		}
+		switch {
+		case target.message == nil && root.message == nil:
+			for _, child := range root.children {
+				child.parent = target
+			}
+			target.children = append(target.children, root.children...)
+		case target.message == nil || (!isReply(target) && reply):
+			root.parent = target
+			target.children = append(target.children, root)
+		default:
+			// make both messages siblings under a new placeholder, which
+			// takes the place of the target
+			moved := &threadContainer{
+				messageID: target.messageID,
+				message:   target.message,
+				parent:    target,
+				children:  target.children,
+			}
+			for _, child := range moved.children {
+				child.parent = moved
+			}
+			root.parent = target
+			target.messageID, target.message = "", nil
+			target.children = []*threadContainer{moved, root}
+		}
+	}
+	return grouped
+}
+
+// containerSubject returns the subject of a container without its reply
+// prefixes, using its first child for placeholders, and whether it was a reply
+func containerSubject(container *threadContainer) (string, bool) {
+	if container.message == nil {
+		if len(container.children) == 0 {
+			return "", false
+		}
+		return containerSubject(container.children[0])
+	}
+	subject := container.message.email.Subject
+	var reply bool
+	for {
+		trimmed := subjectPrefixRegex.ReplaceAllString(subject, "")
+		if trimmed == subject {
+			break
+		}
+		subject, reply = trimmed, true
+	}
+	return strings.TrimSpace(subject), reply
+}
+
+// isReply reports if the subject of a container has reply prefixes
+func isReply(container *threadContainer) bool {
+	_, reply := containerSubject(container)
+	return reply
+}
+
+// containerDate returns the date of a container, using the earliest
+// date of its children for placeholders
+func containerDate(container *threadContainer) time.Time {
+	if container.message != nil {
+		return container.message.email.Date
+	}
+	var earliest time.Time
+	for i, child := range container.children {
+		if date := containerDate(child); i == 0 || date.Before(earliest) {
+			earliest = date
+		}
+	}
+	return earliest
+}
+
+// sortContainers orders containers, and their children, by date
+func sortContainers(containers []*threadContainer) {
+	for _, container := range containers {
+		sortContainers(container.children)
+	}
+	sort.SliceStable(containers, func(i, j int) bool {
+		di, dj := containerDate(containers[i]), containerDate(containers[j])
+		if !di.Equal(dj) {
+			return di.Before(dj)
+		}
+		return containers[i].messageID < containers[j].messageID
+	})
+}
+
+// lessThreadMessage orders messages by date, then hash
+func lessThreadMessage(a, b *threadMessage) bool {
+	if !a.email.Date.Equal(b.email.Date) {
+		return a.email.Date.Before(b.email.Date)
+	}
+	return a.hash < b.hash
+}
+
+// toThreadNode converts a container and its children to a thread node
+func toThreadNode(container *threadContainer) pb.ThreadNode {
+	node := pb.ThreadNode{MessageID: container.messageID}
+	if container.message != nil {
+		node.Hash = container.message.hash
+		node.MessageID = container.message.email.MessageID
+	}
+	for _, child := range container.children {
+		node.Children = append(node.Children, toThreadNode(child))
+	}
+	return node
+}
+
+// countMessages returns the number of messages under a container
+func countMessages(container *threadContainer) uint64 {
+	var count uint64
+	if container.message != nil {
+		count++
+	}
+	for _, child := range container.children {
+		count += countMessages(child)
+	}
+	return count
+}
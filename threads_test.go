@@ -0,0 +1,80 @@
+package ipldeml
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestBuildThreads(t *testing.T) {
+	start := time.Date(2020, 3, 27, 0, 0, 0, 0, time.UTC)
+	newMessage := func(hour int, id, subject string, inReplyTo string, refs ...string) threadMessage {
+		email := &pb.Email{
+			MessageID:  id,
+			Subject:    subject,
+			Date:       start.Add(time.Duration(hour) * time.Hour),
+			References: refs,
+		}
+		if inReplyTo != "" {
+			email.InReplyTo = []string{inReplyTo}
+		}
+		return threadMessage{hash: "hash-" + id, email: email}
+	}
+	messages := []threadMessage{
+		newMessage(0, "a", "Plan", ""),
+		newMessage(1, "b", "Re: Plan", "", "a"),
+		newMessage(2, "c", "Re: Plan", "b"),
+		// references lost, threaded by subject
+		newMessage(3, "f", "RE: Fwd: Plan", ""),
+		// replies to a message which was not stored
+		newMessage(4, "d", "Other", "", "missing"),
+		newMessage(5, "e", "Re: Other", "missing"),
+		newMessage(6, "g", "Unrelated", ""),
+		// references which loop, the first link made is kept
+		newMessage(7, "h", "Loop", "", "i"),
+		newMessage(8, "i", "Re: Loop", "", "h"),
+	}
+	threads := buildThreads(messages)
+	want := []struct {
+		subject string
+		count   uint64
+		root    string
+		replies int
+	}{
+		{"Plan", 4, "hash-a", 2},
+		{"Other", 2, "", 2},
+		{"Unrelated", 1, "hash-g", 0},
+		{"Loop", 2, "hash-i", 1},
+	}
+	if len(threads) != len(want) {
+		t.Fatalf("got %v threads, want %v", len(threads), len(want))
+	}
+	for i, w := range want {
+		thread := threads[i]
+		if thread.Subject != w.subject || thread.Count != w.count ||
+			thread.Root.Hash != w.root || len(thread.Root.Children) != w.replies {
+			t.Fatalf("bad thread %v: %v", i, thread)
+		}
+	}
+	// b is a reply to a, and c a reply to b
+	plan := threads[0].Root
+	if plan.Children[0].Hash != "hash-b" || plan.Children[0].Children[0].Hash != "hash-c" || plan.Children[1].Hash != "hash-f" {
+		t.Fatal("bad plan thread", plan)
+	}
+	if threads[1].Root.MessageID != "missing" {
+		t.Fatal("bad placeholder", threads[1].Root)
+	}
+	// the order messages are threaded in doesn't change the threads
+	for n := 0; n < 10; n++ {
+		shuffled := append([]threadMessage(nil), messages...)
+		rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		for i, thread := range buildThreads(shuffled) {
+			if !proto.Equal(&thread, &threads[i]) {
+				t.Fatal("threads differ when shuffled")
+			}
+		}
+	}
+}
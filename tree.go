@@ -0,0 +1,296 @@
+package ipldeml
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"sort"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains a prolly tree of TreeNode objects from keys to values, for
+// indexes which are looked up by key and grow with the archive, so updating
+// a few keys only rewrites the nodes on the paths to them
+
+// nodes hold this many entries or children on average
+const treeFanout = 32
+
+// treeNodeStore stores the nodes of a tree
+type treeNodeStore interface {
+	getTreeNode(hash string) (*pb.TreeNode, error)
+	putTreeNode(node *pb.TreeNode) (string, error)
+}
+
+// treeMerge returns the value stored when value is added for key, which had
+// the existing value, or a nil existing value if the key is new
+type treeMerge func(key, existing, value []byte) ([]byte, error)
+
+// putTreeEntries adds entries with unique keys to the tree with the given
+// root, combining their values with merge, or replacing existing values if
+// merge is nil, and returns the new root. an empty root starts a new tree
+func putTreeEntries(store treeNodeStore, root string, entries []pb.TreeEntry, merge treeMerge) (string, error) {
+	entries = append([]pb.TreeEntry(nil), entries...)
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].Key, entries[j].Key) < 0 })
+	node := new(pb.TreeNode)
+	if root != "" {
+		if len(entries) == 0 {
+			return root, nil
+		}
+		var err error
+		if node, err = store.getTreeNode(root); err != nil {
+			return "", err
+		}
+	}
+	children, err := updateTreeNode(store, node, entries, merge)
+	if err != nil {
+		return "", err
+	}
+	for level := node.Level + 1; len(children) > 1; level++ {
+		if children, err = putTreeChildren(store, level, children); err != nil {
+			return "", err
+		}
+	}
+	return children[0].Hash, nil
+}
+
+// updateTreeNode adds entries to a node, returning the nodes replacing it
+func updateTreeNode(store treeNodeStore, node *pb.TreeNode, entries []pb.TreeEntry, merge treeMerge) ([]pb.TreeChild, error) {
+	if node.Level == 0 {
+		merged, err := mergeTreeEntries(node.Entries, entries, merge)
+		if err != nil {
+			return nil, err
+		}
+		return putTreeLeaves(store, merged)
+	}
+	var (
+		children []pb.TreeChild
+		start    int
+	)
+	for i, child := range node.Children {
+		// each child holds the keys after the last key of the child before
+		// it, and the last child also holds any later keys
+		end := len(entries)
+		if i < len(node.Children)-1 {
+			end = start
+			for end < len(entries) && bytes.Compare(entries[end].Key, child.Last) <= 0 {
+				end++
+			}
+		}
+		if end == start {
+			children = append(children, child)
+			continue
+		}
+		childNode, err := store.getTreeNode(child.Hash)
+		if err != nil {
+			return nil, err
+		}
+		replaced, err := updateTreeNode(store, childNode, entries[start:end], merge)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, replaced...)
+		start = end
+	}
+	return putTreeChildren(store, node.Level, children)
+}
+
+// mergeTreeEntries adds sorted entries to the sorted existing entries
+func mergeTreeEntries(existing, entries []pb.TreeEntry, merge treeMerge) ([]pb.TreeEntry, error) {
+	merged := make([]pb.TreeEntry, 0, len(existing)+len(entries))
+	for len(existing) > 0 || len(entries) > 0 {
+		if len(entries) == 0 || (len(existing) > 0 && bytes.Compare(existing[0].Key, entries[0].Key) < 0) {
+			merged = append(merged, existing[0])
+			existing = existing[1:]
+			continue
+		}
+		entry := entries[0]
+		entries = entries[1:]
+		var old []byte
+		if len(existing) > 0 && bytes.Equal(existing[0].Key, entry.Key) {
+			old = existing[0].Value
+			existing = existing[1:]
+		}
+		if merge != nil {
+			var err error
+			if entry.Value, err = merge(entry.Key, old, entry.Value); err != nil {
+				return nil, err
+			}
+		}
+		merged = append(merged, entry)
+	}
+	return merged, nil
+}
+
+// putTreeLeaves stores entries as leaves split at their boundaries
+func putTreeLeaves(store treeNodeStore, entries []pb.TreeEntry) ([]pb.TreeChild, error) {
+	if len(entries) == 0 {
+		child, err := putTreeRef(store, new(pb.TreeNode))
+		return []pb.TreeChild{child}, err
+	}
+	var (
+		children []pb.TreeChild
+		start    int
+	)
+	for i := range entries {
+		if i < len(entries)-1 && !isTreeBoundary(entries[i].Key, 0) {
+			continue
+		}
+		child, err := putTreeRef(store, &pb.TreeNode{Entries: entries[start : i+1]})
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+		start = i + 1
+	}
+	return children, nil
+}
+
+// putTreeChildren stores children as nodes at level split at their boundaries
+func putTreeChildren(store treeNodeStore, level uint32, children []pb.TreeChild) ([]pb.TreeChild, error) {
+	var (
+		parents []pb.TreeChild
+		start   int
+	)
+	for i := range children {
+		if i < len(children)-1 && !isTreeBoundary(children[i].Last, level) {
+			continue
+		}
+		parent, err := putTreeRef(store, &pb.TreeNode{Level: level, Children: children[start : i+1]})
+		if err != nil {
+			return nil, err
+		}
+		parents = append(parents, parent)
+		start = i + 1
+	}
+	return parents, nil
+}
+
+// putTreeRef stores a node, returning the link to it
+func putTreeRef(store treeNodeStore, node *pb.TreeNode) (pb.TreeChild, error) {
+	var ref pb.TreeChild
+	if n := len(node.Entries); n > 0 {
+		ref.Last, ref.Count = node.Entries[n-1].Key, uint64(n)
+	}
+	if n := len(node.Children); n > 0 {
+		ref.Last = node.Children[n-1].Last
+	}
+	for _, child := range node.Children {
+		ref.Count += child.Count
+	}
+	var err error
+	ref.Hash, err = store.putTreeNode(node)
+	return ref, err
+}
+
+// getTreeValue returns the value of key in the tree with the given root, and
+// false if the key isn't in the tree
+func getTreeValue(store treeNodeStore, root string, key []byte) ([]byte, bool, error) {
+	if root == "" {
+		return nil, false, nil
+	}
+	node, err := store.getTreeNode(root)
+	if err != nil {
+		return nil, false, err
+	}
+	for node.Level > 0 {
+		children := node.Children
+		i := sort.Search(len(children), func(i int) bool { return bytes.Compare(children[i].Last, key) >= 0 })
+		if i == len(children) {
+			return nil, false, nil
+		}
+		if node, err = store.getTreeNode(children[i].Hash); err != nil {
+			return nil, false, err
+		}
+	}
+	entries := node.Entries
+	i := sort.Search(len(entries), func(i int) bool { return bytes.Compare(entries[i].Key, key) >= 0 })
+	if i == len(entries) || !bytes.Equal(entries[i].Key, key) {
+		return nil, false, nil
+	}
+	return entries[i].Value, true, nil
+}
+
+// walkTree calls fn with every entry in the tree with the given root, in key order
+func walkTree(store treeNodeStore, root string, fn func(entry pb.TreeEntry) error) error {
+	return walkTreeRange(store, root, nil, nil, fn)
+}
+
+// walkTreeRange calls fn with the entries in the tree with the given root
+// whose key is at or after start, and before end, in key order. nil keys
+// leave the range unbounded
+func walkTreeRange(store treeNodeStore, root string, start, end []byte, fn func(entry pb.TreeEntry) error) error {
+	if root == "" {
+		return nil
+	}
+	_, err := walkTreeNode(store, root, start, end, fn)
+	return err
+}
+
+// walkTreeNode calls fn with the entries in range under the node at hash,
+// returning true once an entry past the range was reached
+func walkTreeNode(store treeNodeStore, hash string, start, end []byte, fn func(entry pb.TreeEntry) error) (bool, error) {
+	node, err := store.getTreeNode(hash)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range node.Entries {
+		if end != nil && bytes.Compare(entry.Key, end) >= 0 {
+			return true, nil
+		}
+		if start != nil && bytes.Compare(entry.Key, start) < 0 {
+			continue
+		}
+		if err := fn(entry); err != nil {
+			return false, err
+		}
+	}
+	for _, child := range node.Children {
+		if start != nil && bytes.Compare(child.Last, start) < 0 {
+			continue
+		}
+		done, err := walkTreeNode(store, child.Hash, start, end, fn)
+		if err != nil || done {
+			return done, err
+		}
+	}
+	return false, nil
+}
+
+// isTreeBoundary reports if a node at level ends at key, which is the case
+// for about one in treeFanout keys
+func isTreeBoundary(key []byte, level uint32) bool {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], level)
+	h := sha256.New()
+	h.Write(buf[:])
+	h.Write(key)
+	return binary.BigEndian.Uint32(h.Sum(nil)) < math.MaxUint32/treeFanout
+}
+
+// getTreeNode returns the tree node stored at hash
+func (c *Converter) getTreeNode(hash string) (*pb.TreeNode, error) {
+	data, err := c.getBytes(hash)
+	if err != nil {
+		return nil, err
+	}
+	node := new(pb.TreeNode)
+	if err := node.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(node.Version); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// putTreeNode stores a tree node, returning its hash
+func (c *Converter) putTreeNode(node *pb.TreeNode) (string, error) {
+	node.Version = compatibleVersion
+	data, err := node.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return c.putBytes(data)
+}
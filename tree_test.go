@@ -0,0 +1,143 @@
+package ipldeml
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// memoryTreeStore keeps tree nodes in memory, keyed by their digest
+type memoryTreeStore map[string][]byte
+
+func (s memoryTreeStore) getTreeNode(hash string) (*pb.TreeNode, error) {
+	node := new(pb.TreeNode)
+	if err := node.Unmarshal(s[hash]); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (s memoryTreeStore) putTreeNode(node *pb.TreeNode) (string, error) {
+	data, err := node.Marshal()
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(data)
+	hash := hex.EncodeToString(digest[:])
+	s[hash] = data
+	return hash, nil
+}
+
+func testTreeEntries(n int) []pb.TreeEntry {
+	entries := make([]pb.TreeEntry, n)
+	for i := range entries {
+		entries[i] = pb.TreeEntry{Key: []byte("key-" + strconv.Itoa(i)), Value: []byte(strconv.Itoa(i))}
+	}
+	return entries
+}
+
+func TestTreeOrderIndependent(t *testing.T) {
+	entries := testTreeEntries(2000)
+	store := memoryTreeStore{}
+	whole, err := putTreeEntries(store, "", entries, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := store.getTreeNode(whole)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.Level < 2 {
+		t.Fatal("expected a tree at least 3 levels deep, got level", root.Level)
+	}
+	// adding the same entries shuffled in batches, with repeats, gives the same root
+	shuffled := append([]pb.TreeEntry(nil), entries...)
+	rand.New(rand.NewSource(2)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	var batched string
+	for i := 0; i < len(shuffled); i += 150 {
+		end := i + 150
+		if end > len(shuffled) {
+			end = len(shuffled)
+		}
+		if batched, err = putTreeEntries(store, batched, shuffled[i:end], nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if batched, err = putTreeEntries(store, batched, entries[:10], nil); err != nil {
+		t.Fatal(err)
+	}
+	if batched != whole {
+		t.Fatal("adding entries in batches gave a different root")
+	}
+	var count uint64
+	for _, child := range root.Children {
+		count += child.Count
+	}
+	if count != uint64(len(entries)) {
+		t.Fatal("bad count", count)
+	}
+}
+
+func TestTreeValues(t *testing.T) {
+	entries := testTreeEntries(2000)
+	store := memoryTreeStore{}
+	root, err := putTreeEntries(store, "", entries, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries[:100] {
+		value, ok, err := getTreeValue(store, root, entry.Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok || !bytes.Equal(value, entry.Value) {
+			t.Fatalf("getTreeValue(%s) = %s, %v", entry.Key, value, ok)
+		}
+	}
+	for _, key := range []string{"", "key-", "key-99999", "zzz"} {
+		if _, ok, err := getTreeValue(store, root, []byte(key)); err != nil || ok {
+			t.Fatalf("getTreeValue(%s) = %v, %v", key, ok, err)
+		}
+	}
+	// merging a value only rewrites the path to it
+	before := len(store)
+	merge := func(key, existing, value []byte) ([]byte, error) {
+		return append(append([]byte(nil), existing...), value...), nil
+	}
+	updated, err := putTreeEntries(store, root, []pb.TreeEntry{{Key: entries[5].Key, Value: []byte("+")}}, merge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written := len(store) - before; written > 4 {
+		t.Fatal("updating a value wrote", written, "nodes")
+	}
+	value, _, err := getTreeValue(store, updated, entries[5].Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(value) != "5+" {
+		t.Fatalf("merged value is %s", value)
+	}
+	var walked []pb.TreeEntry
+	if err := walkTree(store, updated, func(entry pb.TreeEntry) error {
+		walked = append(walked, entry)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(walked) != len(entries) {
+		t.Fatalf("walked %v entries, want %v", len(walked), len(entries))
+	}
+	for i := 1; i < len(walked); i++ {
+		if bytes.Compare(walked[i-1].Key, walked[i].Key) >= 0 {
+			t.Fatal("entries are not in order")
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package ipldeml
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+)
+
+// contains helpers for versioning the schema of stored objects, so objects
+// stored by older versions can be read and migrated
+
+const (
+	// EmailVersion is the newest schema version. version 2 added delivery
+	// envelopes and delta encoded replies, which hold the message behind
+	// fields a version 1 reader ignores, so they are written with it to be
+	// refused by older readers instead of decoding as an empty email. objects
+	// stored before versions were recorded have a version of 0
+	EmailVersion = 2
+	// compatibleVersion is written with every other object, which version 1
+	// readers decode in full
+	compatibleVersion = 1
+)
+
+// checkVersion returns an error for objects stored with a newer schema
+func checkVersion(version uint32) error {
+	return checkSupportedVersion(version, EmailVersion)
+}
+
+// checkSupportedVersion returns an error for objects stored with a newer
+// schema than supported, which is how readers of older versions check
+func checkSupportedVersion(version, supported uint32) error {
+	if version > supported {
+		return errors.New("unsupported schema version " + strconv.FormatUint(uint64(version), 10))
+	}
+	return nil
+}
+
+// upgradeEmail brings a decoded email stored with an older schema
+// version up to the current version
+func upgradeEmail(email *pb.Email) error {
+	if err := checkVersion(email.Version); err != nil {
+		return err
+	}
+	if email.Version == 0 {
+		// objects stored before resent blocks existed only have a single block
+		if email.Resent != nil && len(email.ResentBlocks) == 0 {
+			email.ResentBlocks = []pb.Resent{*email.Resent}
+		}
+		email.Resent = nil
+	}
+	email.Version = EmailVersion
+	return nil
+}
+
+// MigrateEmail rewrites an email, and any emails attached to it, with the
+// current schema version, returning the hash of the rewritten email. emails
+// already stored with the current version and the same options give back
+// the same hash
+func (c *Converter) MigrateEmail(hash string) (string, error) {
+	email, err := c.migrateEmail(hash, c.GetEmail)
+	if err != nil {
+		return "", err
+	}
+	return c.PutEmail(email)
+}
+
+// MigrateEmailChunked is like MigrateEmail, but for emails in the chunked format
+func (c *Converter) MigrateEmailChunked(hash string) (string, error) {
+	email, err := c.migrateEmail(hash, c.GetEmailChunked)
+	if err != nil {
+		return "", err
+	}
+	return c.PutEmailChunked(email)
+}
+
+// migrateEmail retrieves an email, migrating its attached emails which are
+// always stored in the unixfs format
+func (c *Converter) migrateEmail(hash string, get func(string) (*pb.Email, error)) (*pb.Email, error) {
+	email, err := get(hash)
+	if err != nil {
+		return nil, err
+	}
+	for i, attach := range email.Attachments {
+		if attach.EmailHash == "" {
+			continue
+		}
+		if email.Attachments[i].EmailHash, err = c.MigrateEmail(attach.EmailHash); err != nil {
+			return nil, err
+		}
+	}
+	return email, nil
+}
@@ -0,0 +1,64 @@
+package ipldeml
+
+import (
+	"testing"
+
+	"github.com/RTradeLtd/ipld-eml/pb"
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestUpgradeEmail(t *testing.T) {
+	resent := pb.Resent{ResentMessageId: "resent@example.com"}
+	tests := []struct {
+		name    string
+		email   *pb.Email
+		want    *pb.Email
+		wantErr bool
+	}{
+		{"legacy", &pb.Email{Subject: "hello"}, &pb.Email{Subject: "hello", Version: EmailVersion}, false},
+		{"legacy resent", &pb.Email{Resent: &resent}, &pb.Email{ResentBlocks: []pb.Resent{resent}, Version: EmailVersion}, false},
+		{"version 1", &pb.Email{Subject: "hello", Version: 1}, &pb.Email{Subject: "hello", Version: EmailVersion}, false},
+		{"current", &pb.Email{Subject: "hello", Version: EmailVersion}, &pb.Email{Subject: "hello", Version: EmailVersion}, false},
+		{"future", &pb.Email{Version: EmailVersion + 1}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := upgradeEmail(tt.email)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("upgradeEmail() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !proto.Equal(tt.email, tt.want) {
+				t.Fatalf("upgradeEmail() got %v, want %v", tt.email, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvelopeVersion(t *testing.T) {
+	email := &pb.Email{
+		Subject: "hello",
+		Headers: pb.Header{Values: map[string]pb.Headers{"Received": {Values: []string{"from mx.example.com"}}}},
+	}
+	envelope := splitEnvelope(email)
+	// a version 1 reader would decode the envelope as an empty email
+	if checkSupportedVersion(envelope.Version, 1) == nil {
+		t.Fatal("envelope is accepted by version 1 readers")
+	}
+	if checkSupportedVersion(compatibleVersion, 1) != nil {
+		t.Fatal("plain objects are refused by version 1 readers")
+	}
+	if err := upgradeEmail(envelope); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeltaVersion(t *testing.T) {
+	email := deltaEmail(&pb.EmailDelta{BaseHash: "base", Depth: 1}, "summary")
+	// a version 1 reader would decode the reply as an empty email
+	if checkSupportedVersion(email.Version, 1) == nil {
+		t.Fatal("delta is accepted by version 1 readers")
+	}
+	if err := upgradeEmail(email); err != nil || email.Delta == nil {
+		t.Fatal("delta is refused by current readers", err)
+	}
+}